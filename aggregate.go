@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	aggSummaryInterval = 10 * time.Minute
+	minDBBackoff       = time.Second
+	maxDBBackoff       = 5 * time.Minute
+)
+
+// feedError is a failure that only concerns one feed, like an unreachable
+// server or an unparsable document. The aggregator moves on to the next
+// feed when it sees one.
+type feedError struct {
+	Feed database.Feed
+	Err  error
+}
+
+func (e *feedError) Error() string {
+	return "feed " + e.Feed.Name + " (" + e.Feed.Url + "): " + e.Err.Error()
+}
+
+func (e *feedError) Unwrap() error {
+	return e.Err
+}
+
+type aggErrorKind int
+
+const (
+	aggOK aggErrorKind = iota
+	aggNoFeeds
+	aggFeedFailure
+	aggTransientDB
+	aggDBFailure
+)
+
+func (k aggErrorKind) String() string {
+	switch k {
+	case aggOK:
+		return "ok"
+	case aggNoFeeds:
+		return "no feeds due"
+	case aggFeedFailure:
+		return "feed failure"
+	case aggTransientDB:
+		return "database unavailable"
+	default:
+		return "database failure"
+	}
+}
+
+func classifyAggError(err error) aggErrorKind {
+	var fe *feedError
+	switch {
+	case err == nil:
+		return aggOK
+	case errors.As(err, &fe):
+		return aggFeedFailure
+	case errors.Is(err, sql.ErrNoRows):
+		return aggNoFeeds
+	case isTransientDBError(err):
+		return aggTransientDB
+	default:
+		return aggDBFailure
+	}
+}
+
+// isTransientDBError reports whether err looks like the database being
+// unreachable or restarting, as opposed to a problem with a query.
+func isTransientDBError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		// connection_exception, insufficient_resources, operator_intervention
+		case "08", "53", "57":
+			return true
+		}
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		// another process holds the write lock for longer than busy_timeout
+		switch sqliteErr.Code {
+		case sqlite3.ErrBusy, sqlite3.ErrLocked:
+			return true
+		}
+	}
+
+	return strings.Contains(err.Error(), "connection refused")
+}
+
+func nextBackoff(current time.Duration) time.Duration {
+	if current < minDBBackoff {
+		return minDBBackoff
+	}
+	return min(2*current, maxDBBackoff)
+}
+
+// aggSummary counts scrape outcomes between two periodic log lines.
+type aggSummary struct {
+	since  time.Time
+	counts map[aggErrorKind]int
+}
+
+func newAggSummary() *aggSummary {
+	s := &aggSummary{}
+	s.reset()
+	return s
+}
+
+func (s *aggSummary) record(kind aggErrorKind) {
+	s.counts[kind]++
+}
+
+func (s *aggSummary) due() bool {
+	return time.Since(s.since) >= aggSummaryInterval
+}
+
+func (s *aggSummary) reset() {
+	s.since = time.Now()
+	s.counts = make(map[aggErrorKind]int)
+}
+
+func (s *aggSummary) log() {
+	log.Printf("aggregation summary since %s: %d succeeded, %d feed failure(s), %d database error(s), %d transient database outage(s)",
+		s.since.Format(time.RFC3339),
+		s.counts[aggOK],
+		s.counts[aggFeedFailure],
+		s.counts[aggDBFailure],
+		s.counts[aggTransientDB],
+	)
+}
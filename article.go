@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+	"github.com/gaba-bouliva/gator/internal/readability"
+)
+
+const articleFetchTimeout = 10 * time.Second
+
+// maxArticleSize is how much of an article page is read; the rest of a
+// larger page is ignored.
+const maxArticleSize = 5 << 20
+
+func handleFullArticle(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 2)
+	if err != nil {
+		return err
+	}
+
+	var enabled bool
+	switch cmd.Arguments[1] {
+	case "on":
+		enabled = true
+	case "off":
+		enabled = false
+	default:
+		return fmt.Errorf("%s command expects on or off as second argument", cmd.Name)
+	}
+
+	// the setting applies to every follower and makes agg download each
+	// article, so it is managed like the feed itself
+	feed, err := managedFeed(a, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	err = a.DB.SetFeedFetchFullArticle(context.Background(), database.SetFeedFetchFullArticleParams{
+		FetchFullArticle: enabled,
+		UpdatedAt:        time.Now(),
+		ID:               feed.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("full article fetching for %s: %s\n", feed.Name, cmd.Arguments[1])
+	return nil
+}
+
+func fetchArticle(ctx context.Context, articleURL string) (string, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, articleFetchTimeout)
+	defer cancelFunc()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", articleURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("User-Agent", "gator")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching article %s: unexpected status %s", articleURL, res.Status)
+	}
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return "", fmt.Errorf("fetching article %s: not an HTML page (%s)", articleURL, res.Header.Get("Content-Type"))
+	}
+
+	return readability.Extract(io.LimitReader(res.Body, maxArticleSize))
+}
+
+// fetchArticles downloads the full article of every item not stored yet
+// when feed has full article fetching enabled, keyed by link. Articles that
+// fail to download are left out.
+func fetchArticles(a *application.App, feed database.Feed, items []postItem) (map[string]sql.NullString, error) {
+	articles := make(map[string]sql.NullString)
+	if !feed.FetchFullArticle || len(items) == 0 {
+		return articles, nil
+	}
+
+	stored, err := findDuplicates(a.DB, items)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if _, ok := item.duplicateOf(stored); ok {
+			continue
+		}
+
+		article, err := fetchArticle(context.Background(), item.Link)
+		if err != nil {
+			log.Printf("error fetching full article: %v", err)
+			continue
+		}
+		articles[item.Link] = sql.NullString{String: article, Valid: true}
+	}
+	return articles, nil
+}
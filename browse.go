@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const browseUsage = "[usage] browse [limit] [--all] [--tag <tag>] [--feed <url>] [--since <date>] [--until <date>] [--sort newest|oldest] [--after <post id>] [--page <n>] [--clustered]"
+
+// handleBrowse lists posts from the feeds user follows, newest first by
+// default. Pages are keyset based: --after continues after a post id, which
+// stays stable while new posts arrive, and --page walks that many pages in.
+// Posts hidden by filters do not count toward the limit.
+func handleBrowse(a *application.App, cmd application.Command, user database.User) error {
+	params := database.GetPostsForUserParams{
+		UserID:     user.ID,
+		UnreadOnly: true,
+		PostLimit:  2,
+	}
+	clustered := false
+	oldestFirst := false
+	page := 1
+	for i := 0; i < len(cmd.Arguments); i++ {
+		arg := cmd.Arguments[i]
+		if arg == "--clustered" {
+			clustered = true
+			continue
+		}
+		if arg == "--all" {
+			params.UnreadOnly = false
+			continue
+		}
+		if !strings.HasPrefix(arg, "--") {
+			limit, err := strconv.Atoi(arg)
+			if err != nil || limit <= 0 {
+				return fmt.Errorf(browseUsage)
+			}
+			params.PostLimit = int32(limit)
+			continue
+		}
+		if i+1 >= len(cmd.Arguments) {
+			return fmt.Errorf(browseUsage)
+		}
+		i++
+		value := cmd.Arguments[i]
+
+		switch arg {
+		case "--tag":
+			params.Tag = sql.NullString{String: normalizeTag(value), Valid: true}
+		case "--feed":
+			follow, err := getFollowByURL(a, user, value)
+			if err != nil {
+				return err
+			}
+			params.FeedID = sql.NullInt64{Int64: follow.FeedsID, Valid: true}
+		case "--since":
+			since, err := tryParseDate(value)
+			if err != nil {
+				return err
+			}
+			params.Since = sql.NullTime{Time: since, Valid: true}
+		case "--until":
+			until, err := tryParseDate(value)
+			if err != nil {
+				return err
+			}
+			params.Until = sql.NullTime{Time: until, Valid: true}
+		case "--sort":
+			switch value {
+			case "newest":
+				oldestFirst = false
+			case "oldest":
+				oldestFirst = true
+			default:
+				return fmt.Errorf("--sort expects newest or oldest")
+			}
+		case "--after":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("--after expects a post id")
+			}
+			post, err := a.DB.GetPost(context.Background(), id)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("post not found with id %d", id)
+				}
+				return err
+			}
+			params.AfterID = sql.NullInt64{Int64: post.ID, Valid: true}
+			params.AfterPublishedAt = sql.NullTime{Time: post.PublishedAt, Valid: true}
+		case "--page":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("--page expects a page number starting at 1")
+			}
+			page = n
+		default:
+			return fmt.Errorf(browseUsage)
+		}
+	}
+
+	filters, err := loadFilters(a, user)
+	if err != nil {
+		return err
+	}
+	var posts []database.Post
+	var hidden int
+	more := true
+	clusterSizes := make(map[int64]int64)
+	for ; page > 0 && more; page-- {
+		posts, hidden, more, err = browsePage(a, filters, &params, oldestFirst, clustered, clusterSizes)
+		if err != nil {
+			return err
+		}
+	}
+	if len(posts) == 0 {
+		fmt.Println("no posts")
+		return nil
+	}
+
+	feedNames, alsoIn, err := postFeeds(a, user, posts)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		printTimelinePost(post, feedNames[post.ID], alsoIn[post.ID], clusterSizes[post.ID])
+	}
+	if hidden > 0 {
+		fmt.Printf("%d post(s) hidden by filters\n", hidden)
+	}
+	if more {
+		fmt.Printf("next page: --after %d\n", params.AfterID.Int64)
+	}
+	return nil
+}
+
+// browsePage collects the next params.PostLimit posts that filters do not
+// hide, fetching further past hidden posts, and moves the cursor in params
+// after the last post it went through so that the next page neither skips
+// nor repeats a post. It returns the posts, how many were hidden and
+// whether more posts may follow. Clustered pages only keep the newest post
+// of each cluster that filters do not hide, and record its cluster's size
+// in clusterSizes.
+func browsePage(a *application.App, filters userFilters, params *database.GetPostsForUserParams, oldestFirst, clustered bool, clusterSizes map[int64]int64) ([]database.Post, int, bool, error) {
+	limit := int(params.PostLimit)
+	var visible []database.Post
+	hidden := 0
+	more := true
+	for len(visible) < limit && more {
+		posts, err := fetchTimeline(a, *params, oldestFirst)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		var representatives map[int64]bool
+		if clustered {
+			representatives, err = clusterRepresentatives(a, filters, *params, posts, clusterSizes)
+			if err != nil {
+				return nil, 0, false, err
+			}
+		}
+
+		more = len(posts) == limit
+		for i, post := range posts {
+			params.AfterID = sql.NullInt64{Int64: post.ID, Valid: true}
+			params.AfterPublishedAt = sql.NullTime{Time: post.PublishedAt, Valid: true}
+			if filters.hides(post) {
+				hidden++
+				continue
+			}
+			if clustered && !representatives[post.ID] {
+				continue
+			}
+			visible = append(visible, post)
+			if len(visible) == limit {
+				more = more || i < len(posts)-1
+				break
+			}
+		}
+	}
+	return visible, hidden, more, nil
+}
+
+// fetchTimeline returns one page of browse, with one query per sort order
+// so that each can walk the posts' publication date index.
+func fetchTimeline(a *application.App, params database.GetPostsForUserParams, oldestFirst bool) ([]database.Post, error) {
+	var rows []database.GetPostsForUserRow
+	if oldestFirst {
+		oldest, err := a.DB.GetPostsForUserOldestFirst(context.Background(), database.GetPostsForUserOldestFirstParams(params))
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range oldest {
+			rows = append(rows, database.GetPostsForUserRow(row))
+		}
+	} else {
+		var err error
+		rows, err = a.DB.GetPostsForUser(context.Background(), params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	posts := make([]database.Post, 0, len(rows))
+	for _, row := range rows {
+		posts = append(posts, timelinePost(row))
+	}
+	return posts, nil
+}
+
+// timelinePost turns a row of the timeline queries, which leave out the
+// posts' search vector, into a post.
+func timelinePost(row database.GetPostsForUserRow) database.Post {
+	return database.Post{
+		ID:           row.ID,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+		PublishedAt:  row.PublishedAt,
+		Title:        row.Title,
+		Description:  row.Description,
+		Url:          row.Url,
+		FeedID:       row.FeedID,
+		Content:      row.Content,
+		Author:       row.Author,
+		Guid:         row.Guid,
+		CanonicalUrl: row.CanonicalUrl,
+		Fingerprint:  row.Fingerprint,
+		ClusterID:    row.ClusterID,
+	}
+}
+
+// clusterRepresentatives returns the ids of the posts that represent their
+// cluster among posts: the newest member of the cluster that user follows
+// and that filters do not hide, so that a hidden post does not hide its
+// whole story. Posts outside any cluster represent themselves. The number
+// of visible members of each cluster is recorded in clusterSizes.
+func clusterRepresentatives(a *application.App, filters userFilters, params database.GetPostsForUserParams, posts []database.Post, clusterSizes map[int64]int64) (map[int64]bool, error) {
+	representatives := make(map[int64]bool)
+	var clusterIDs []int64
+	for _, post := range posts {
+		if post.ClusterID.Valid {
+			clusterIDs = append(clusterIDs, post.ClusterID.Int64)
+			continue
+		}
+		representatives[post.ID] = true
+	}
+	if len(clusterIDs) == 0 {
+		return representatives, nil
+	}
+
+	members, err := a.DB.GetClusterMembersForUser(context.Background(), database.GetClusterMembersForUserParams{
+		ClusterIds: clusterIDs,
+		UserID:     params.UserID,
+		FeedID:     params.FeedID,
+		Tag:        params.Tag,
+		UnreadOnly: params.UnreadOnly,
+		Since:      params.Since,
+		Until:      params.Until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newest := make(map[int64]database.Post)
+	sizes := make(map[int64]int64)
+	for _, row := range members {
+		member := timelinePost(database.GetPostsForUserRow(row))
+		if filters.hides(member) {
+			continue
+		}
+		clusterID := member.ClusterID.Int64
+		sizes[clusterID]++
+		current, ok := newest[clusterID]
+		if !ok || member.PublishedAt.After(current.PublishedAt) ||
+			(member.PublishedAt.Equal(current.PublishedAt) && member.ID > current.ID) {
+			newest[clusterID] = member
+		}
+	}
+
+	for clusterID, post := range newest {
+		representatives[post.ID] = true
+		clusterSizes[post.ID] = sizes[clusterID]
+	}
+	return representatives, nil
+}
+
+// printTimelinePost prints a post as browse lists it: id and title, then
+// date, feed and url, then the other feeds and related posts, if any.
+func printTimelinePost(post database.Post, feedName string, alsoIn []string, clusterSize int64) {
+	fmt.Printf("* [%d] %s\n", post.ID, post.Title)
+	details := []string{post.PublishedAt.Format("2006-01-02 15:04")}
+	if feedName != "" {
+		details = append(details, feedName)
+	}
+	details = append(details, post.Url)
+	fmt.Printf("  %s\n", strings.Join(details, " | "))
+
+	var extra []string
+	if len(alsoIn) > 0 {
+		extra = append(extra, "also in: "+strings.Join(alsoIn, ", "))
+	}
+	if clusterSize > 1 {
+		extra = append(extra, fmt.Sprintf("+%d related post(s)", clusterSize-1))
+	}
+	if len(extra) > 0 {
+		fmt.Printf("  %s\n", strings.Join(extra, " | "))
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+	"github.com/gaba-bouliva/gator/internal/minhash"
+)
+
+const (
+	// clusterWindow is how far apart two posts may be published and still
+	// be considered coverage of the same story.
+	clusterWindow = 48 * time.Hour
+	// clusterThreshold is the estimated shingle similarity above which two
+	// posts are put in the same cluster.
+	clusterThreshold = 0.5
+)
+
+// clusterPosts stores the signatures of newly created posts and assigns
+// them to clusters. Posts are handled in id order, so a post can join the
+// cluster of one created just before it in the same batch.
+func clusterPosts(q database.Querier, posts []database.CreatePostsRow) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	windowStart, windowEnd := posts[0].PublishedAt, posts[0].PublishedAt
+	for _, post := range posts {
+		if post.PublishedAt.Before(windowStart) {
+			windowStart = post.PublishedAt
+		}
+		if post.PublishedAt.After(windowEnd) {
+			windowEnd = post.PublishedAt
+		}
+	}
+	candidates, err := q.GetClusterCandidates(context.Background(), database.GetClusterCandidatesParams{
+		WindowStart: windowStart.Add(-clusterWindow),
+		WindowEnd:   windowEnd.Add(clusterWindow),
+	})
+	if err != nil {
+		return err
+	}
+
+	var clusters database.SetPostClustersParams
+	var signatures database.CreatePostSignaturesParams
+	for _, post := range posts {
+		sig := minhash.Compute(post.Title + " " + post.Description)
+		if sig == nil {
+			continue
+		}
+		var clusterID sql.NullInt64
+		if match := findCluster(candidates, sig, post.PublishedAt); match >= 0 {
+			clusterID = candidates[match].ClusterID
+			if !clusterID.Valid {
+				// the post joins a post that was not in a cluster yet, so
+				// a cluster is started for both of them
+				id, err := q.CreateCluster(context.Background(), time.Now())
+				if err != nil {
+					return err
+				}
+				clusterID = sql.NullInt64{Int64: id, Valid: true}
+				candidates[match].ClusterID = clusterID
+				clusters.PostIds = append(clusters.PostIds, candidates[match].PostID)
+				clusters.ClusterIds = append(clusters.ClusterIds, id)
+			}
+			clusters.PostIds = append(clusters.PostIds, post.ID)
+			clusters.ClusterIds = append(clusters.ClusterIds, clusterID.Int64)
+		}
+		candidates = append(candidates, database.GetClusterCandidatesRow{
+			PostID:      post.ID,
+			PublishedAt: post.PublishedAt,
+			ClusterID:   clusterID,
+			Minhash:     sig.Encode(),
+		})
+		signatures.PostIds = append(signatures.PostIds, post.ID)
+		signatures.PublishedAts = append(signatures.PublishedAts, post.PublishedAt)
+		signatures.Minhashes = append(signatures.Minhashes, sig.Encode())
+	}
+
+	if len(clusters.PostIds) > 0 {
+		err = q.SetPostClusters(context.Background(), clusters)
+		if err != nil {
+			return err
+		}
+	}
+	if len(signatures.PostIds) == 0 {
+		return nil
+	}
+	return q.CreatePostSignatures(context.Background(), signatures)
+}
+
+// findCluster returns the index in candidates of the post most similar to
+// a post with signature sig published at publishedAt, whose cluster the
+// post joins. It returns -1 when none of candidates is similar enough and
+// published within clusterWindow.
+func findCluster(candidates []database.GetClusterCandidatesRow, sig minhash.Signature, publishedAt time.Time) int {
+	best := -1
+	bestSimilarity := clusterThreshold
+	for idx, candidate := range candidates {
+		if candidate.PublishedAt.Before(publishedAt.Add(-clusterWindow)) || candidate.PublishedAt.After(publishedAt.Add(clusterWindow)) {
+			continue
+		}
+		similarity := minhash.Similarity(sig, minhash.Decode(candidate.Minhash))
+		if similarity < bestSimilarity {
+			continue
+		}
+		bestSimilarity = similarity
+		best = idx
+	}
+	return best
+}
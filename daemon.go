@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// errPidFileLocked is returned by lockFile when another process holds the
+// lock.
+var errPidFileLocked = errors.New("pidfile is locked")
+
+// acquirePidFile records the current process in path and holds a lock on
+// it, failing when another live process already holds it. The lock is
+// released by the system when the process exits, so a pidfile left behind
+// by a crashed daemon is simply taken over. The returned func removes the
+// pidfile.
+func acquirePidFile(path string) (func(), error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		previous := strings.TrimSpace(string(data))
+
+		err = lockFile(file)
+		if err != nil {
+			file.Close()
+			if err == errPidFileLocked {
+				return nil, fmt.Errorf("agg is already running with pid %s (pidfile %s)", previous, path)
+			}
+			return nil, err
+		}
+		// the daemon that held the lock removes path before releasing it,
+		// so the file locked here may no longer be the one at path
+		current, err := os.Stat(path)
+		info, statErr := file.Stat()
+		if err != nil || statErr != nil || !os.SameFile(current, info) {
+			file.Close()
+			continue
+		}
+
+		if previous != "" {
+			fmt.Println("replacing stale pidfile", path)
+		}
+		err = writePid(file)
+		if err != nil {
+			os.Remove(path)
+			file.Close()
+			return nil, err
+		}
+		return func() {
+			os.Remove(path)
+			file.Close()
+		}, nil
+	}
+	return nil, fmt.Errorf("could not acquire pidfile %s", path)
+}
+
+// writePid replaces the contents of file with the current process id.
+func writePid(file *os.File) error {
+	err := file.Truncate(0)
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return err
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// lockFile leaves file unlocked where flock is not available; the pidfile
+// then only records the pid and does not prevent a second daemon.
+func lockFile(file *os.File) error {
+	return nil
+}
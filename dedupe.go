@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// canonicalURL normalizes a post link so the same article syndicated with
+// different tracking parameters, fragments or host casing compares equal.
+func canonicalURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return strings.TrimSpace(rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+	u.Host = strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || lower == "ref" || lower == "source" || lower == "fbclid" || lower == "gclid" {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// globalGUID returns guid when it is usable as a cross-feed identity. Many
+// feeds use bare numbers as guids, which are only unique within the feed,
+// so only URI-shaped guids (urls, tag: and urn: ids) are kept.
+func globalGUID(guid string) string {
+	guid = strings.TrimSpace(guid)
+	if !strings.Contains(guid, ":") {
+		return ""
+	}
+	return guid
+}
+
+// postFingerprint hashes the normalized title and publication day of a post
+// together with the host of its canonical url. Generic titles such as
+// "Weekly links" are published the same day by unrelated sites, so the
+// title and day alone only identify a post within one site, which catches
+// a site republishing an article under a new url.
+func postFingerprint(title string, publishedAt time.Time, canonical string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(title)), " ")
+	if normalized == "" {
+		return ""
+	}
+	u, err := url.Parse(canonical)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(normalized + "|" + publishedAt.UTC().Format("2006-01-02") + "|" + u.Host))
+	return hex.EncodeToString(sum[:])
+}
+
+// postItem is a feed item about to be saved as a post, along with the keys
+// it is deduplicated by.
+type postItem struct {
+	RSSItem
+	publishedAt  time.Time
+	canonicalURL string
+	guid         string
+	fingerprint  string
+}
+
+// maxColumnLength is the length of the VARCHAR columns posts are stored
+// in; a longer value would make the whole batch fail to insert.
+const maxColumnLength = 255
+
+// newPostItems returns the items of rssFeed that can be saved. Items
+// without a link, with a link too long to store or with an unparsable date
+// are skipped; titles and authors too long to store are cut short.
+func newPostItems(rssFeed *RSSFeed) []postItem {
+	items := make([]postItem, 0, len(rssFeed.Channel.Items))
+	for _, item := range rssFeed.Channel.Items {
+		if item.Link == "" {
+			log.Printf("skipping item %q: no link", item.Title)
+			continue
+		}
+		canonical := canonicalURL(item.Link)
+		if utf8.RuneCountInString(item.Link) > maxColumnLength || utf8.RuneCountInString(canonical) > maxColumnLength {
+			log.Printf("skipping item %q: link longer than %d characters", item.Title, maxColumnLength)
+			continue
+		}
+		publishedAt, err := tryParseDate(item.PubDate)
+		if err != nil {
+			log.Printf("skipping item %q: %v", item.Title, err)
+			continue
+		}
+		guid := globalGUID(item.GUID)
+		if utf8.RuneCountInString(guid) > maxColumnLength {
+			// the guid is only a dedupe key, the link still identifies the post
+			guid = ""
+		}
+		item.Title = truncate(item.Title, maxColumnLength)
+		item.Author = truncate(item.Author, maxColumnLength)
+		items = append(items, postItem{
+			RSSItem:      item,
+			publishedAt:  publishedAt,
+			canonicalURL: canonical,
+			guid:         guid,
+			fingerprint:  postFingerprint(item.Title, publishedAt, canonical),
+		})
+	}
+	return items
+}
+
+// truncate cuts s to at most n characters.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// dedupeKeys returns the keys of a post; two posts sharing any key are
+// duplicates. Empty guids and fingerprints identify nothing.
+func dedupeKeys(url, canonical, guid, fingerprint string) []string {
+	keys := []string{"url:" + url, "canonical:" + canonical}
+	if guid != "" {
+		keys = append(keys, "guid:"+guid)
+	}
+	if fingerprint != "" {
+		keys = append(keys, "fingerprint:"+fingerprint)
+	}
+	return keys
+}
+
+func (p postItem) keys() []string {
+	return dedupeKeys(p.Link, p.canonicalURL, p.guid, p.fingerprint)
+}
+
+// duplicateOf returns the id of the post in index that p duplicates.
+func (p postItem) duplicateOf(index map[string]int64) (int64, bool) {
+	for _, key := range p.keys() {
+		if id, ok := index[key]; ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// findDuplicates looks up the stored posts that any of items duplicates,
+// indexed by their dedupe keys.
+func findDuplicates(q database.Querier, items []postItem) (map[string]int64, error) {
+	var params database.FindDuplicatePostsParams
+	for _, item := range items {
+		params.Urls = append(params.Urls, item.Link)
+		params.CanonicalUrls = append(params.CanonicalUrls, item.canonicalURL)
+		params.Guids = append(params.Guids, item.guid)
+		params.Fingerprints = append(params.Fingerprints, item.fingerprint)
+	}
+	posts, err := q.FindDuplicatePosts(context.Background(), params)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int64)
+	for _, post := range posts {
+		for _, key := range dedupeKeys(post.Url, post.CanonicalUrl, post.Guid, post.Fingerprint) {
+			index[key] = post.ID
+		}
+	}
+	return index, nil
+}
+
+// postFeeds returns, for each post, the name of the feed it belongs to and
+// the names of the other feeds the post was also found in. Feeds are named
+// by user's own title for them when they set one.
+func postFeeds(a *application.App, user database.User, posts []database.Post) (map[int64]string, map[int64][]string, error) {
+	ids := make([]int64, 0, len(posts))
+	feedIDs := make(map[int64]int64, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+		feedIDs[post.ID] = post.FeedID
+	}
+
+	sources, err := a.DB.GetPostSources(context.Background(), database.GetPostSourcesParams{
+		UserID:  user.ID,
+		PostIds: ids,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	feedNames := make(map[int64]string, len(posts))
+	alsoIn := make(map[int64][]string)
+	for _, source := range sources {
+		if source.FeedID == feedIDs[source.PostID] {
+			feedNames[source.PostID] = source.FeedName
+			continue
+		}
+		alsoIn[source.PostID] = append(alsoIn[source.PostID], source.FeedName)
+	}
+	return feedNames, alsoIn, nil
+}
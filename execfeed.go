@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const execFeedTimeout = 30 * time.Second
+
+// execFeedWaitDelay is how long a timed out command's output is still
+// waited for once it has been killed, in case a process outside its
+// process group keeps the output open.
+const execFeedWaitDelay = 2 * time.Second
+
+// runExecFeed runs command through the shell and parses its stdout as an
+// RSS, Atom or JSON Feed document, like newsboat's exec: urls. The command's
+// stderr is included in the returned error when it fails.
+func runExecFeed(ctx context.Context, command string) (*RSSFeed, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, execFeedTimeout)
+	defer cancelFunc()
+
+	var stdout, stderr bytes.Buffer
+	execCmd := exec.CommandContext(ctx, "sh", "-c", command)
+	execCmd.Stdout = &stdout
+	execCmd.Stderr = &stderr
+	// killing only sh would leave its children running, holding stdout
+	// open and blocking Run until they exit
+	killProcessGroupOnCancel(execCmd)
+	execCmd.WaitDelay = execFeedWaitDelay
+
+	err := execCmd.Run()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", execFeedTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("running %q: %w: %s", command, err, msg)
+		}
+		return nil, fmt.Errorf("running %q: %w", command, err)
+	}
+
+	return parseFeed(stdout.Bytes())
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os/exec"
+
+// killProcessGroupOnCancel leaves cmd as is where process groups are not
+// available; WaitDelay still bounds how long Run blocks after a timeout.
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+var filterFields = []string{"title", "description", "author", "url", "feed"}
+
+// postFilter is a compiled kill-file rule. A post matching any of a user's
+// filters is hidden from them.
+type postFilter struct {
+	field   string
+	keyword string
+	regex   *regexp.Regexp
+}
+
+func handleFilter(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Arguments[0] {
+	case "add":
+		return handleFilterAdd(a, cmd, user)
+	case "list":
+		return handleFilterList(a, user)
+	case "remove":
+		return handleFilterRemove(a, cmd, user)
+	default:
+		return fmt.Errorf("[usage] filter add|list|remove <arguments>")
+	}
+}
+
+func handleFilterAdd(a *application.App, cmd application.Command, user database.User) error {
+	if len(cmd.Arguments) < 4 {
+		return fmt.Errorf("[usage] filter add <%s> <keyword|regex> <pattern>", strings.Join(filterFields, "|"))
+	}
+
+	filter := database.CreateFilterParams{
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		UserID:    user.ID,
+		Field:     cmd.Arguments[1],
+		Kind:      cmd.Arguments[2],
+		Pattern:   strings.Join(cmd.Arguments[3:], " "),
+	}
+
+	_, err := compileFilter(database.Filter{
+		Field:   filter.Field,
+		Kind:    filter.Kind,
+		Pattern: filter.Pattern,
+	})
+	if err != nil {
+		return err
+	}
+
+	createdFilter, err := a.DB.CreateFilter(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("filter %d added\n", createdFilter.ID)
+	return nil
+}
+
+func handleFilterList(a *application.App, user database.User) error {
+	filters, err := a.DB.GetFiltersForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range filters {
+		fmt.Printf("* %d: %s %s %q\n", f.ID, f.Field, f.Kind, f.Pattern)
+	}
+	return nil
+}
+
+func handleFilterRemove(a *application.App, cmd application.Command, user database.User) error {
+	if len(cmd.Arguments) < 2 {
+		return fmt.Errorf("[usage] filter remove <id>")
+	}
+
+	id, err := strconv.ParseInt(cmd.Arguments[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("filter remove expects a filter id, got %s", cmd.Arguments[1])
+	}
+
+	deleted, err := a.DB.DeleteFilter(context.Background(), database.DeleteFilterParams{
+		ID:     int32(id),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("filter not found with id %d", id)
+	}
+
+	fmt.Printf("filter %d removed\n", id)
+	return nil
+}
+
+func compileFilter(f database.Filter) (postFilter, error) {
+	valid := false
+	for _, field := range filterFields {
+		if f.Field == field {
+			valid = true
+		}
+	}
+	if !valid {
+		return postFilter{}, fmt.Errorf("invalid filter field %q, expected one of %s", f.Field, strings.Join(filterFields, ", "))
+	}
+
+	switch f.Kind {
+	case "keyword":
+		return postFilter{field: f.Field, keyword: strings.ToLower(f.Pattern)}, nil
+	case "regex":
+		re, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return postFilter{}, fmt.Errorf("invalid filter regex %q: %w", f.Pattern, err)
+		}
+		return postFilter{field: f.Field, regex: re}, nil
+	default:
+		return postFilter{}, fmt.Errorf("invalid filter kind %q, expected keyword or regex", f.Kind)
+	}
+}
+
+func (f postFilter) matches(post database.Post, feed database.Feed) bool {
+	var values []string
+	switch f.field {
+	case "title":
+		values = []string{post.Title}
+	case "description":
+		values = []string{post.Description}
+	case "author":
+		values = []string{post.Author}
+	case "url":
+		values = []string{post.Url}
+	case "feed":
+		values = []string{feed.Name, feed.Url}
+	}
+
+	for _, value := range values {
+		if f.regex != nil && f.regex.MatchString(value) {
+			return true
+		}
+		if f.regex == nil && strings.Contains(strings.ToLower(value), f.keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// userFilters are a user's compiled filters, along with the feeds posts
+// may belong to for matching the feed field.
+type userFilters struct {
+	filters []postFilter
+	feeds   map[int64]database.Feed
+}
+
+// loadFilters compiles user's filters.
+func loadFilters(a *application.App, user database.User) (userFilters, error) {
+	filters, err := a.DB.GetFiltersForUser(context.Background(), user.ID)
+	if err != nil {
+		return userFilters{}, err
+	}
+	if len(filters) == 0 {
+		return userFilters{}, nil
+	}
+
+	compiled := make([]postFilter, 0, len(filters))
+	for _, f := range filters {
+		pf, err := compileFilter(f)
+		if err != nil {
+			return userFilters{}, err
+		}
+		compiled = append(compiled, pf)
+	}
+
+	feeds, err := a.DB.GetFeeds(context.Background())
+	if err != nil {
+		return userFilters{}, err
+	}
+	feedsByID := make(map[int64]database.Feed, len(feeds))
+	for _, feed := range feeds {
+		feedsByID[feed.ID] = feed
+	}
+	return userFilters{filters: compiled, feeds: feedsByID}, nil
+}
+
+// hides reports whether any of the filters matches post.
+func (f userFilters) hides(post database.Post) bool {
+	for _, pf := range f.filters {
+		if pf.matches(post, f.feeds[post.FeedID]) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFilters drops the posts matched by any of user's filters and returns
+// the remaining posts along with how many were hidden.
+func applyFilters(a *application.App, user database.User, posts []database.Post) ([]database.Post, int, error) {
+	filters, err := loadFilters(a, user)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var visible []database.Post
+	hidden := 0
+	for _, post := range posts {
+		if filters.hides(post) {
+			hidden++
+			continue
+		}
+		visible = append(visible, post)
+	}
+
+	return visible, hidden, nil
+}
@@ -3,6 +3,9 @@ module github.com/gaba-bouliva/gator
 go 1.23.4
 
 require (
-	github.com/google/uuid v1.6.0
+	github.com/PuerkitoBio/goquery v1.10.3
+	github.com/andybalholm/cascadia v1.3.3
 	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.33
+	golang.org/x/net v0.43.0
 )
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+func handleAddScrapedFeed(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 5)
+	if err != nil {
+		return err
+	}
+
+	selectors := database.CreateFeedSelectorParams{
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		ItemSelector:  cmd.Arguments[2],
+		TitleSelector: cmd.Arguments[3],
+		LinkSelector:  cmd.Arguments[4],
+	}
+	if len(cmd.Arguments) > 5 {
+		selectors.DateSelector = cmd.Arguments[5]
+	}
+	if len(cmd.Arguments) > 6 {
+		selectors.SummarySelector = cmd.Arguments[6]
+	}
+
+	for _, sel := range []string{
+		selectors.ItemSelector,
+		selectors.TitleSelector,
+		selectors.LinkSelector,
+		selectors.DateSelector,
+		selectors.SummarySelector,
+	} {
+		if sel == "" {
+			continue
+		}
+		if _, err := cascadia.Compile(sel); err != nil {
+			return fmt.Errorf("invalid selector %q: %w", sel, err)
+		}
+	}
+
+	var createdFeed database.Feed
+	err = a.InTx(context.Background(), func(q database.Querier) error {
+		createdFeed, err = addFeed(q, user, cmd.Arguments[0], cmd.Arguments[1])
+		if err != nil {
+			return err
+		}
+		selectors.FeedID = createdFeed.ID
+		_, err = q.CreateFeedSelector(context.Background(), selectors)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%+v\n", createdFeed)
+
+	return nil
+}
+
+// fetchHTMLFeed downloads pageURL and turns every element matching the
+// item selector into an RSSItem, so scraped pages share the RSS post path.
+func fetchHTMLFeed(ctx context.Context, pageURL string, selectors database.FeedSelector) (*RSSFeed, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "gator")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching page %s: unexpected status %s", pageURL, res.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var rssFeed RSSFeed
+	rssFeed.Channel.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	rssFeed.Channel.Link = pageURL
+
+	doc.Find(selectors.ItemSelector).Each(func(_ int, s *goquery.Selection) {
+		item := RSSItem{
+			Title: strings.TrimSpace(s.Find(selectors.TitleSelector).First().Text()),
+			Link:  selectLink(s, selectors.LinkSelector, base),
+		}
+		if item.Title == "" || item.Link == "" {
+			return
+		}
+		if selectors.DateSelector != "" {
+			dateSel := s.Find(selectors.DateSelector).First()
+			if datetime, ok := dateSel.Attr("datetime"); ok {
+				item.PubDate = datetime
+			} else {
+				item.PubDate = strings.TrimSpace(dateSel.Text())
+			}
+		}
+		// pages often show dates only meant for people; an item whose date
+		// is missing or unparsable is dated when it is first seen, as the
+		// link dedupe keeps that first copy
+		if _, err := tryParseDate(item.PubDate); err != nil {
+			item.PubDate = time.Now().Format(time.RFC1123Z)
+		}
+		if selectors.SummarySelector != "" {
+			item.Description = strings.TrimSpace(s.Find(selectors.SummarySelector).First().Text())
+		}
+		rssFeed.Channel.Items = append(rssFeed.Channel.Items, item)
+	})
+
+	return &rssFeed, nil
+}
+
+// selectLink returns the absolute href of the first element matching sel,
+// falling back to the first anchor inside it.
+func selectLink(s *goquery.Selection, sel string, base *url.URL) string {
+	linkSel := s.Find(sel).First()
+	href, ok := linkSel.Attr("href")
+	if !ok {
+		href, ok = linkSel.Find("a[href]").First().Attr("href")
+	}
+	if !ok {
+		return ""
+	}
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// handleIngest reads an RSS or Atom document from stdin and stores its items
+// as posts of an existing feed.
+func handleIngest(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+
+	feeds, err := findFeeds(a, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+	if len(feeds) > 1 {
+		return fmt.Errorf("%d feeds are named %s, pass the feed's url instead", len(feeds), cmd.Arguments[0])
+	}
+	feed := feeds[0]
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	rssFeed, err := parseFeed(data)
+	if err != nil {
+		return err
+	}
+
+	inserted, skipped, err := savePosts(a, feed, rssFeed)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ingested %d new post(s) into %s, skipped %d item(s)\n", inserted, feed.Name, skipped)
+	return nil
+}
+
+func readFileFeed(fileURL string) (*RSSFeed, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, fmt.Errorf("unsupported file url host %q", u.Host)
+	}
+
+	data, err := os.ReadFile(u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFeed(data)
+}
@@ -1,24 +1,33 @@
 package application
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
 	"github.com/gaba-bouliva/gator/internal/config"
 	"github.com/gaba-bouliva/gator/internal/database"
+	"github.com/gaba-bouliva/gator/internal/migrate"
 )
 
 type App struct {
 	Config   *config.Config
 	Commands map[string]func(*App, Command) error
-	DB       *database.Queries
+	DB       database.Querier
+	Migrator *migrate.Migrator
+	conn     *sql.DB
+	withTx   func(*sql.Tx) database.Querier
 }
 
-func NewApp(db *sql.DB) *App {
+// NewApp returns an App running queries through db. withTx binds the
+// same queries to a transaction on conn, as database.Queries.WithTx does.
+func NewApp(conn *sql.DB, db database.Querier, withTx func(*sql.Tx) database.Querier) *App {
 	return &App{
 		Config:   &config.Config{},
 		Commands: make(map[string]func(*App, Command) error),
-		DB:       database.New(db),
+		DB:       db,
+		conn:     conn,
+		withTx:   withTx,
 	}
 }
 
@@ -33,3 +42,19 @@ func (a *App) RunCMD(cmd Command) error {
 	}
 	return handler(a, cmd)
 }
+
+// InTx runs fn with queries that all belong to one transaction, which is
+// committed if fn returns nil and rolled back otherwise.
+func (a *App) InTx(ctx context.Context, fn func(q database.Querier) error) error {
+	tx, err := a.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(a.withTx(tx))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -4,17 +4,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	DBUrl           string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DBUrl             string `json:"db_url"`
+	CurrentUserName   string `json:"current_user_name"`
+	AllowExecFeeds    bool   `json:"allow_exec_feeds"`
+	AggPidFile        string `json:"agg_pid_file"`
+	PollMinInterval   string `json:"poll_min_interval"`
+	PollMaxInterval   string `json:"poll_max_interval"`
+	RetentionMaxAge   string `json:"retention_max_age"`
+	RetentionMaxPosts int    `json:"retention_max_posts_per_feed"`
+	AutoPrune         bool   `json:"auto_prune"`
 }
 
 const configFileName = ".gatorconfig.json"
 
+const defaultPidFileName = ".gator-agg.pid"
+
+const (
+	defaultPollMinInterval = 15 * time.Minute
+	defaultPollMaxInterval = 24 * time.Hour
+)
+
 func Read() (*Config, error) {
 	filePath, err := getConfigFilePath()
 	if err != nil {
@@ -67,6 +85,90 @@ func (c *Config) GetCurrentUser() (string, error) {
 	return oldConfig.CurrentUserName, nil
 }
 
+// PidFilePath returns the pidfile used to prevent running two agg daemons,
+// defaulting to a file next to the config file.
+func (c *Config) PidFilePath() (string, error) {
+	if c.AggPidFile != "" {
+		return c.AggPidFile, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, defaultPidFileName), nil
+}
+
+// PollBounds returns the shortest and longest interval the scheduler may
+// wait between two fetches of a feed.
+func (c *Config) PollBounds() (time.Duration, time.Duration, error) {
+	minInterval, maxInterval := defaultPollMinInterval, defaultPollMaxInterval
+	var err error
+	if c.PollMinInterval != "" {
+		minInterval, err = time.ParseDuration(c.PollMinInterval)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid poll_min_interval: %w", err)
+		}
+	}
+	if c.PollMaxInterval != "" {
+		maxInterval, err = time.ParseDuration(c.PollMaxInterval)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid poll_max_interval: %w", err)
+		}
+	}
+	if minInterval <= 0 || maxInterval < minInterval {
+		return 0, 0, fmt.Errorf("poll_min_interval must be positive and not above poll_max_interval")
+	}
+	return minInterval, maxInterval, nil
+}
+
+// Retention returns the global retention policy: posts older than maxAge
+// and posts beyond the newest maxPosts of a feed are pruned. Zero disables
+// either limit.
+func (c *Config) Retention() (time.Duration, int, error) {
+	var maxAge time.Duration
+	if c.RetentionMaxAge != "" {
+		var err error
+		maxAge, err = ParseAge(c.RetentionMaxAge)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid retention_max_age: %w", err)
+		}
+	}
+	if c.RetentionMaxPosts < 0 || c.RetentionMaxPosts > math.MaxInt32 {
+		return 0, 0, fmt.Errorf("retention_max_posts_per_feed must be between 0 and %d", math.MaxInt32)
+	}
+	return maxAge, c.RetentionMaxPosts, nil
+}
+
+// MaxAge is the longest age ParseAge accepts, as ages are stored as a
+// 32-bit number of seconds.
+const MaxAge = math.MaxInt32 * time.Second
+
+// ParseAge parses a duration like time.ParseDuration, additionally
+// accepting a whole number of days such as "90d".
+func ParseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		if n > int(MaxAge/(24*time.Hour)) {
+			return 0, fmt.Errorf("age %q is longer than the maximum of %s", s, MaxAge)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	if age > MaxAge {
+		return 0, fmt.Errorf("age %q is longer than the maximum of %s", s, MaxAge)
+	}
+	return age, nil
+}
+
 func getConfigFilePath() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -11,31 +11,108 @@ import (
 	"time"
 )
 
+const claimFeed = `-- name: ClaimFeed :one
+UPDATE feeds
+SET lease_owner = $1,
+    lease_expires_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => $2::int)
+WHERE id = $3
+  AND (lease_expires_at IS NULL OR lease_expires_at < now() AT TIME ZONE 'UTC')
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, fetch_full_article, last_fetch_error, lease_owner, lease_expires_at, poll_interval_seconds, next_fetch_at
+`
+
+type ClaimFeedParams struct {
+	LeaseOwner   sql.NullString
+	LeaseSeconds int32
+	ID           int64
+}
+
+func (q *Queries) ClaimFeed(ctx context.Context, arg ClaimFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, claimFeed, arg.LeaseOwner, arg.LeaseSeconds, arg.ID)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.FetchFullArticle,
+		&i.LastFetchError,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PollIntervalSeconds,
+		&i.NextFetchAt,
+	)
+	return i, err
+}
+
+const claimNextFeed = `-- name: ClaimNextFeed :one
+UPDATE feeds
+SET lease_owner = $1,
+    lease_expires_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => $2::int),
+    last_fetched_at = $3,
+    updated_at = $3
+WHERE id = (
+    SELECT id FROM feeds
+    WHERE (lease_expires_at IS NULL OR lease_expires_at < now() AT TIME ZONE 'UTC')
+      AND (next_fetch_at IS NULL OR next_fetch_at <= now() AT TIME ZONE 'UTC')
+    ORDER BY next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, fetch_full_article, last_fetch_error, lease_owner, lease_expires_at, poll_interval_seconds, next_fetch_at
+`
+
+type ClaimNextFeedParams struct {
+	LeaseOwner   sql.NullString
+	LeaseSeconds int32
+	ClaimedAt    sql.NullTime
+}
+
+func (q *Queries) ClaimNextFeed(ctx context.Context, arg ClaimNextFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, claimNextFeed, arg.LeaseOwner, arg.LeaseSeconds, arg.ClaimedAt)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.FetchFullArticle,
+		&i.LastFetchError,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PollIntervalSeconds,
+		&i.NextFetchAt,
+	)
+	return i, err
+}
+
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (created_at, updated_at, name, url, user_id)
 VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5,
-    $6
+    $5
 )
-RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, fetch_full_article, last_fetch_error, lease_owner, lease_expires_at, poll_interval_seconds, next_fetch_at
 `
 
 type CreateFeedParams struct {
-	ID        int32
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
 	Url       string
-	UserID    int32
+	UserID    sql.NullInt64
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
 	row := q.db.QueryRowContext(ctx, createFeed,
-		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.Name,
@@ -51,12 +128,27 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.FetchFullArticle,
+		&i.LastFetchError,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PollIntervalSeconds,
+		&i.NextFetchAt,
 	)
 	return i, err
 }
 
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id FROM feeds WHERE url = $1 LIMIT 1
+SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id, fetch_full_article, last_fetch_error, lease_owner, lease_expires_at, poll_interval_seconds, next_fetch_at FROM feeds WHERE url = $1 LIMIT 1
 `
 
 func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
@@ -70,12 +162,18 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.FetchFullArticle,
+		&i.LastFetchError,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PollIntervalSeconds,
+		&i.NextFetchAt,
 	)
 	return i, err
 }
 
 const getFeeds = `-- name: GetFeeds :many
-SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id FROM feeds
+SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id, fetch_full_article, last_fetch_error, lease_owner, lease_expires_at, poll_interval_seconds, next_fetch_at FROM feeds
 `
 
 func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
@@ -95,6 +193,12 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Name,
 			&i.Url,
 			&i.UserID,
+			&i.FetchFullArticle,
+			&i.LastFetchError,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PollIntervalSeconds,
+			&i.NextFetchAt,
 		); err != nil {
 			return nil, err
 		}
@@ -109,23 +213,130 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 	return items, nil
 }
 
-const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id FROM feeds ORDER BY last_fetched_at DESC NULLS FIRST LIMIT 1
+const getFeedsByName = `-- name: GetFeedsByName :many
+SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id, fetch_full_article, last_fetch_error, lease_owner, lease_expires_at, poll_interval_seconds, next_fetch_at FROM feeds WHERE name = $1
 `
 
-func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
-	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
-	var i Feed
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.LastFetchedAt,
-		&i.Name,
-		&i.Url,
-		&i.UserID,
-	)
-	return i, err
+func (q *Queries) GetFeedsByName(ctx context.Context, name string) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsByName, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastFetchedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.FetchFullArticle,
+			&i.LastFetchError,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PollIntervalSeconds,
+			&i.NextFetchAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedsByOwner = `-- name: GetFeedsByOwner :many
+SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id, fetch_full_article, last_fetch_error, lease_owner, lease_expires_at, poll_interval_seconds, next_fetch_at FROM feeds WHERE user_id = $1 ORDER BY name
+`
+
+func (q *Queries) GetFeedsByOwner(ctx context.Context, userID sql.NullInt64) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsByOwner, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastFetchedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.FetchFullArticle,
+			&i.LastFetchError,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PollIntervalSeconds,
+			&i.NextFetchAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollowedFeeds = `-- name: GetFollowedFeeds :many
+SELECT feeds.id, feeds.created_at, feeds.updated_at, feeds.last_fetched_at, feeds.name, feeds.url, feeds.user_id, feeds.fetch_full_article, feeds.last_fetch_error, feeds.lease_owner, feeds.lease_expires_at, feeds.poll_interval_seconds, feeds.next_fetch_at FROM feeds
+JOIN feeds_follows ON feeds_follows.feeds_id = feeds.id
+WHERE feeds_follows.user_id = $1
+ORDER BY feeds.name
+`
+
+func (q *Queries) GetFollowedFeeds(ctx context.Context, userID int64) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowedFeeds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastFetchedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.FetchFullArticle,
+			&i.LastFetchError,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PollIntervalSeconds,
+			&i.NextFetchAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const markFeedFetched = `-- name: MarkFeedFetched :exec
@@ -136,10 +347,124 @@ WHERE id = $3
 type MarkFeedFetchedParams struct {
 	LastFetchedAt sql.NullTime
 	UpdatedAt     time.Time
-	ID            int32
+	ID            int64
 }
 
 func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
 	_, err := q.db.ExecContext(ctx, markFeedFetched, arg.LastFetchedAt, arg.UpdatedAt, arg.ID)
 	return err
 }
+
+const releaseFeedLease = `-- name: ReleaseFeedLease :exec
+UPDATE feeds SET lease_owner = NULL, lease_expires_at = NULL
+WHERE id = $1 AND lease_owner = $2
+`
+
+type ReleaseFeedLeaseParams struct {
+	ID         int64
+	LeaseOwner sql.NullString
+}
+
+func (q *Queries) ReleaseFeedLease(ctx context.Context, arg ReleaseFeedLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, releaseFeedLease, arg.ID, arg.LeaseOwner)
+	return err
+}
+
+const renameFeed = `-- name: RenameFeed :exec
+UPDATE feeds SET name = $1, updated_at = $2
+WHERE id = $3
+`
+
+type RenameFeedParams struct {
+	Name      string
+	UpdatedAt time.Time
+	ID        int64
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) error {
+	_, err := q.db.ExecContext(ctx, renameFeed, arg.Name, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const renewFeedLease = `-- name: RenewFeedLease :execrows
+UPDATE feeds SET lease_expires_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => $1::int)
+WHERE id = $2 AND lease_owner = $3
+`
+
+type RenewFeedLeaseParams struct {
+	LeaseSeconds int32
+	ID           int64
+	LeaseOwner   sql.NullString
+}
+
+func (q *Queries) RenewFeedLease(ctx context.Context, arg RenewFeedLeaseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renewFeedLease, arg.LeaseSeconds, arg.ID, arg.LeaseOwner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedFetchError = `-- name: SetFeedFetchError :exec
+UPDATE feeds SET last_fetch_error = $1
+WHERE id = $2
+`
+
+type SetFeedFetchErrorParams struct {
+	LastFetchError sql.NullString
+	ID             int64
+}
+
+func (q *Queries) SetFeedFetchError(ctx context.Context, arg SetFeedFetchErrorParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchError, arg.LastFetchError, arg.ID)
+	return err
+}
+
+const setFeedFetchFullArticle = `-- name: SetFeedFetchFullArticle :exec
+UPDATE feeds SET fetch_full_article = $1, updated_at = $2
+WHERE id = $3
+`
+
+type SetFeedFetchFullArticleParams struct {
+	FetchFullArticle bool
+	UpdatedAt        time.Time
+	ID               int64
+}
+
+func (q *Queries) SetFeedFetchFullArticle(ctx context.Context, arg SetFeedFetchFullArticleParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchFullArticle, arg.FetchFullArticle, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setFeedOwner = `-- name: SetFeedOwner :exec
+UPDATE feeds SET user_id = $1, updated_at = $2
+WHERE id = $3
+`
+
+type SetFeedOwnerParams struct {
+	UserID    sql.NullInt64
+	UpdatedAt time.Time
+	ID        int64
+}
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedOwner, arg.UserID, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setFeedSchedule = `-- name: SetFeedSchedule :exec
+UPDATE feeds
+SET poll_interval_seconds = $1::int,
+    next_fetch_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => $1::int)
+WHERE id = $2
+`
+
+type SetFeedScheduleParams struct {
+	PollIntervalSeconds int32
+	ID                  int64
+}
+
+func (q *Queries) SetFeedSchedule(ctx context.Context, arg SetFeedScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedSchedule, arg.PollIntervalSeconds, arg.ID)
+	return err
+}
@@ -7,18 +7,18 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
 const createFeedFollow = `-- name: CreateFeedFollow :one
 WITH inserted AS (
-    INSERT INTO feeds_follows (id, created_at, updated_at, user_id, feeds_id)
+    INSERT INTO feeds_follows (created_at, updated_at, user_id, feeds_id)
     VALUES (
         $1,
         $2,
         $3,
-        $4,
-        $5
+        $4
     )
     RETURNING id, created_at, updated_at, user_id, feeds_id
 )
@@ -36,26 +36,24 @@ JOIN feeds ON inserted.feeds_id = feeds.id
 `
 
 type CreateFeedFollowParams struct {
-	ID        int32
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	UserID    int32
-	FeedsID   int32
+	UserID    int64
+	FeedsID   int64
 }
 
 type CreateFeedFollowRow struct {
-	ID        int32
+	ID        int64
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	UserID    int32
-	FeedsID   int32
+	UserID    int64
+	FeedsID   int64
 	UserName  string
 	FeedName  string
 }
 
 func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
 	row := q.db.QueryRowContext(ctx, createFeedFollow,
-		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.UserID,
@@ -79,8 +77,8 @@ DELETE FROM feeds_follows WHERE user_id = $1 AND feeds_id = $2
 `
 
 type DeleteFeedFollowParams struct {
-	UserID  int32
-	FeedsID int32
+	UserID  int64
+	FeedsID int64
 }
 
 func (q *Queries) DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowParams) error {
@@ -88,6 +86,56 @@ func (q *Queries) DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowPara
 	return err
 }
 
+const getFeedFollow = `-- name: GetFeedFollow :one
+SELECT id, created_at, updated_at, user_id, feeds_id, title FROM feeds_follows WHERE user_id = $1 AND feeds_id = $2
+`
+
+type GetFeedFollowParams struct {
+	UserID  int64
+	FeedsID int64
+}
+
+func (q *Queries) GetFeedFollow(ctx context.Context, arg GetFeedFollowParams) (FeedsFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollow, arg.UserID, arg.FeedsID)
+	var i FeedsFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedsID,
+		&i.Title,
+	)
+	return i, err
+}
+
+const getFeedFollowerIDs = `-- name: GetFeedFollowerIDs :many
+SELECT user_id FROM feeds_follows WHERE feeds_id = $1 ORDER BY created_at, id
+`
+
+func (q *Queries) GetFeedFollowerIDs(ctx context.Context, feedsID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowerIDs, feedsID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 SELECT 
     feeds_follows.id, 
@@ -96,7 +144,16 @@ SELECT
     feeds_follows.user_id, 
     feeds_follows.feeds_id, 
     users.name AS user_name, 
-    feeds.name AS feed_name
+    COALESCE(feeds_follows.title, feeds.name) AS feed_name,
+    (
+        SELECT COUNT(*) FROM post_sources
+        WHERE post_sources.feed_id = feeds.id
+          AND NOT EXISTS (
+              SELECT 1 FROM post_reads
+              WHERE post_reads.post_id = post_sources.post_id
+                AND post_reads.user_id = feeds_follows.user_id
+          )
+    ) AS unread_count
 FROM feeds_follows
 JOIN users ON users.id = feeds_follows.user_id
 JOIN feeds ON feeds.id = feeds_follows.feeds_id
@@ -104,16 +161,17 @@ WHERE feeds_follows.user_id = $1
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        int32
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    int32
-	FeedsID   int32
-	UserName  string
-	FeedName  string
+	ID          int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      int64
+	FeedsID     int64
+	UserName    string
+	FeedName    string
+	UnreadCount int64
 }
 
-func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID int32) ([]GetFeedFollowsForUserRow, error) {
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID int64) ([]GetFeedFollowsForUserRow, error) {
 	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
 	if err != nil {
 		return nil, err
@@ -130,6 +188,7 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID int32) ([]Ge
 			&i.FeedsID,
 			&i.UserName,
 			&i.FeedName,
+			&i.UnreadCount,
 		); err != nil {
 			return nil, err
 		}
@@ -143,3 +202,18 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID int32) ([]Ge
 	}
 	return items, nil
 }
+
+const setFollowTitle = `-- name: SetFollowTitle :exec
+UPDATE feeds_follows SET title = $1, updated_at = $2 WHERE id = $3
+`
+
+type SetFollowTitleParams struct {
+	Title     sql.NullString
+	UpdatedAt time.Time
+	ID        int64
+}
+
+func (q *Queries) SetFollowTitle(ctx context.Context, arg SetFollowTitleParams) error {
+	_, err := q.db.ExecContext(ctx, setFollowTitle, arg.Title, arg.UpdatedAt, arg.ID)
+	return err
+}
@@ -9,38 +9,118 @@ import (
 	"time"
 )
 
+type Cluster struct {
+	ID        int64
+	CreatedAt time.Time
+}
+
 type Feed struct {
-	ID            int32
-	CreatedAt     time.Time
+	ID                  int64
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	LastFetchedAt       sql.NullTime
+	Name                string
+	Url                 string
+	UserID              sql.NullInt64
+	FetchFullArticle    bool
+	LastFetchError      sql.NullString
+	LeaseOwner          sql.NullString
+	LeaseExpiresAt      sql.NullTime
+	PollIntervalSeconds sql.NullInt32
+	NextFetchAt         sql.NullTime
+}
+
+type FeedRetention struct {
+	FeedID        int64
 	UpdatedAt     time.Time
-	LastFetchedAt sql.NullTime
-	Name          string
-	Url           string
-	UserID        int32
+	MaxAgeSeconds sql.NullInt32
+	MaxPosts      sql.NullInt32
+}
+
+type FeedSelector struct {
+	FeedID          int64
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ItemSelector    string
+	TitleSelector   string
+	LinkSelector    string
+	DateSelector    string
+	SummarySelector string
 }
 
 type FeedsFollow struct {
+	ID        int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    int64
+	FeedsID   int64
+	Title     sql.NullString
+}
+
+type Filter struct {
 	ID        int32
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	UserID    int32
-	FeedsID   int32
+	UserID    int64
+	Field     string
+	Kind      string
+	Pattern   string
+}
+
+type FollowTag struct {
+	FollowID  int64
+	Tag       string
+	CreatedAt time.Time
 }
 
 type Post struct {
-	ID          int32
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PublishedAt  time.Time
+	Title        string
+	Description  string
+	Url          string
+	FeedID       int64
+	Content      sql.NullString
+	Author       string
+	Guid         string
+	CanonicalUrl string
+	Fingerprint  string
+	ClusterID    sql.NullInt64
+	SearchVector interface{}
+}
+
+type PostRead struct {
+	UserID int64
+	PostID int64
+	ReadAt time.Time
+}
+
+type PostSignature struct {
+	PostID      int64
 	PublishedAt time.Time
-	Title       string
-	Description string
-	Url         string
-	FeedID      int32
+	Minhash     []byte
+}
+
+type PostSource struct {
+	PostID    int64
+	FeedID    int64
+	CreatedAt time.Time
+	Url       string
+}
+
+type Star struct {
+	UserID    int64
+	PostID    int64
+	CreatedAt time.Time
+	Note      string
 }
 
 type User struct {
-	ID        int32
+	ID        int64
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
+	IsAdmin   bool
 }
@@ -7,66 +7,307 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
-)
 
-const createPost = `-- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, published_at, title, description, url, feed_id)
-VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7,
-    $8
+	"github.com/lib/pq"
 )
-RETURNING id, created_at, updated_at, published_at, title, description, url, feed_id
+
+const createPosts = `-- name: CreatePosts :many
+INSERT INTO posts (created_at, updated_at, published_at, title, description, url, feed_id, content, author, guid, canonical_url, fingerprint)
+SELECT
+    $1::timestamp,
+    $1::timestamp,
+    item.published_at,
+    item.title,
+    item.description,
+    item.url,
+    $2::bigint,
+    NULLIF(item.content, ''),
+    item.author,
+    item.guid,
+    item.canonical_url,
+    item.fingerprint
+FROM unnest(
+    $3::timestamp[],
+    $4::text[],
+    $5::text[],
+    $6::text[],
+    $7::text[],
+    $8::text[],
+    $9::text[],
+    $10::text[],
+    $11::text[]
+) AS item(published_at, title, description, url, content, author, guid, canonical_url, fingerprint)
+ON CONFLICT (url) DO NOTHING
+RETURNING id, published_at, title, description, url
 `
 
-type CreatePostParams struct {
-	ID          int32
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+type CreatePostsParams struct {
+	CreatedAt     time.Time
+	FeedID        int64
+	PublishedAts  []time.Time
+	Titles        []string
+	Descriptions  []string
+	Urls          []string
+	Contents      []string
+	Authors       []string
+	Guids         []string
+	CanonicalUrls []string
+	Fingerprints  []string
+}
+
+type CreatePostsRow struct {
+	ID          int64
 	PublishedAt time.Time
 	Title       string
 	Description string
 	Url         string
-	FeedID      int32
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
-	row := q.db.QueryRowContext(ctx, createPost,
-		arg.ID,
+func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) ([]CreatePostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, createPosts,
 		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.PublishedAt,
-		arg.Title,
-		arg.Description,
-		arg.Url,
 		arg.FeedID,
+		pq.Array(arg.PublishedAts),
+		pq.Array(arg.Titles),
+		pq.Array(arg.Descriptions),
+		pq.Array(arg.Urls),
+		pq.Array(arg.Contents),
+		pq.Array(arg.Authors),
+		pq.Array(arg.Guids),
+		pq.Array(arg.CanonicalUrls),
+		pq.Array(arg.Fingerprints),
 	)
-	var i Post
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.PublishedAt,
-		&i.Title,
-		&i.Description,
-		&i.Url,
-		&i.FeedID,
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CreatePostsRow
+	for rows.Next() {
+		var i CreatePostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PublishedAt,
+			&i.Title,
+			&i.Description,
+			&i.Url,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteFeedPosts = `-- name: DeleteFeedPosts :exec
+DELETE FROM posts WHERE feed_id = $1
+`
+
+func (q *Queries) DeleteFeedPosts(ctx context.Context, feedID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedPosts, feedID)
+	return err
+}
+
+const findDuplicatePosts = `-- name: FindDuplicatePosts :many
+SELECT id, url, canonical_url, guid, fingerprint FROM posts
+WHERE url = ANY($1::text[])
+   OR canonical_url = ANY($2::text[])
+   OR (guid <> '' AND guid = ANY($3::text[]))
+   OR (fingerprint <> '' AND fingerprint = ANY($4::text[]))
+`
+
+type FindDuplicatePostsParams struct {
+	Urls          []string
+	CanonicalUrls []string
+	Guids         []string
+	Fingerprints  []string
+}
+
+type FindDuplicatePostsRow struct {
+	ID           int64
+	Url          string
+	CanonicalUrl string
+	Guid         string
+	Fingerprint  string
+}
+
+func (q *Queries) FindDuplicatePosts(ctx context.Context, arg FindDuplicatePostsParams) ([]FindDuplicatePostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, findDuplicatePosts,
+		pq.Array(arg.Urls),
+		pq.Array(arg.CanonicalUrls),
+		pq.Array(arg.Guids),
+		pq.Array(arg.Fingerprints),
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindDuplicatePostsRow
+	for rows.Next() {
+		var i FindDuplicatePostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.CanonicalUrl,
+			&i.Guid,
+			&i.Fingerprint,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getPostByUrl = `-- name: GetPostByUrl :one
-SELECT id, created_at, updated_at, published_at, title, description, url, feed_id FROM posts WHERE url = $1
+const getClusterMembersForUser = `-- name: GetClusterMembersForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE posts.cluster_id = ANY($1::bigint[])
+AND EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = $2
+      AND ($3::bigint IS NULL OR feeds_follows.feeds_id = $3)
+      AND ($4::text IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = $4
+      ))
+)
+AND (NOT $5::boolean OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = $2
+))
+AND ($6::timestamp IS NULL OR posts.published_at >= $6)
+AND ($7::timestamp IS NULL OR posts.published_at < $7)
 `
 
-func (q *Queries) GetPostByUrl(ctx context.Context, url string) (Post, error) {
-	row := q.db.QueryRowContext(ctx, getPostByUrl, url)
+type GetClusterMembersForUserParams struct {
+	ClusterIds []int64
+	UserID     int64
+	FeedID     sql.NullInt64
+	Tag        sql.NullString
+	UnreadOnly bool
+	Since      sql.NullTime
+	Until      sql.NullTime
+}
+
+type GetClusterMembersForUserRow struct {
+	ID           int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PublishedAt  time.Time
+	Title        string
+	Description  string
+	Url          string
+	FeedID       int64
+	Content      sql.NullString
+	Author       string
+	Guid         string
+	CanonicalUrl string
+	Fingerprint  string
+	ClusterID    sql.NullInt64
+}
+
+func (q *Queries) GetClusterMembersForUser(ctx context.Context, arg GetClusterMembersForUserParams) ([]GetClusterMembersForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClusterMembersForUser,
+		pq.Array(arg.ClusterIds),
+		arg.UserID,
+		arg.FeedID,
+		arg.Tag,
+		arg.UnreadOnly,
+		arg.Since,
+		arg.Until,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetClusterMembersForUserRow
+	for rows.Next() {
+		var i GetClusterMembersForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishedAt,
+			&i.Title,
+			&i.Description,
+			&i.Url,
+			&i.FeedID,
+			&i.Content,
+			&i.Author,
+			&i.Guid,
+			&i.CanonicalUrl,
+			&i.Fingerprint,
+			&i.ClusterID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedPublishTimes = `-- name: GetFeedPublishTimes :many
+SELECT posts.published_at FROM post_sources
+JOIN posts ON posts.id = post_sources.post_id
+WHERE post_sources.feed_id = $1
+ORDER BY posts.published_at DESC
+LIMIT $2
+`
+
+type GetFeedPublishTimesParams struct {
+	FeedID int64
+	Limit  int32
+}
+
+func (q *Queries) GetFeedPublishTimes(ctx context.Context, arg GetFeedPublishTimesParams) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedPublishTimes, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var published_at time.Time
+		if err := rows.Scan(&published_at); err != nil {
+			return nil, err
+		}
+		items = append(items, published_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPost = `-- name: GetPost :one
+SELECT id, created_at, updated_at, published_at, title, description, url, feed_id, content, author, guid, canonical_url, fingerprint, cluster_id, search_vector FROM posts WHERE id = $1
+`
+
+func (q *Queries) GetPost(ctx context.Context, id int64) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPost, id)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -77,23 +318,89 @@ func (q *Queries) GetPostByUrl(ctx context.Context, url string) (Post, error) {
 		&i.Description,
 		&i.Url,
 		&i.FeedID,
+		&i.Content,
+		&i.Author,
+		&i.Guid,
+		&i.CanonicalUrl,
+		&i.Fingerprint,
+		&i.ClusterID,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
-const getPosts = `-- name: GetPosts :many
-SELECT id, created_at, updated_at, published_at, title, description, url, feed_id FROM posts ORDER BY published_at DESC LIMIT $1
+const getPostsForUser = `-- name: GetPostsForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = $1
+      AND ($2::bigint IS NULL OR feeds_follows.feeds_id = $2)
+      AND ($3::text IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = $3
+      ))
+)
+AND (NOT $4::boolean OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = $1
+))
+AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+AND ($6::timestamp IS NULL OR posts.published_at < $6)
+AND ($7::bigint IS NULL
+    OR (posts.published_at, posts.id) < ($8::timestamp, $7))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT $9
 `
 
-func (q *Queries) GetPosts(ctx context.Context, limit int32) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPosts, limit)
+type GetPostsForUserParams struct {
+	UserID           int64
+	FeedID           sql.NullInt64
+	Tag              sql.NullString
+	UnreadOnly       bool
+	Since            sql.NullTime
+	Until            sql.NullTime
+	AfterID          sql.NullInt64
+	AfterPublishedAt sql.NullTime
+	PostLimit        int32
+}
+
+type GetPostsForUserRow struct {
+	ID           int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PublishedAt  time.Time
+	Title        string
+	Description  string
+	Url          string
+	FeedID       int64
+	Content      sql.NullString
+	Author       string
+	Guid         string
+	CanonicalUrl string
+	Fingerprint  string
+	ClusterID    sql.NullInt64
+}
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.FeedID,
+		arg.Tag,
+		arg.UnreadOnly,
+		arg.Since,
+		arg.Until,
+		arg.AfterID,
+		arg.AfterPublishedAt,
+		arg.PostLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Post
+	var items []GetPostsForUserRow
 	for rows.Next() {
-		var i Post
+		var i GetPostsForUserRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -103,6 +410,12 @@ func (q *Queries) GetPosts(ctx context.Context, limit int32) ([]Post, error) {
 			&i.Description,
 			&i.Url,
 			&i.FeedID,
+			&i.Content,
+			&i.Author,
+			&i.Guid,
+			&i.CanonicalUrl,
+			&i.Fingerprint,
+			&i.ClusterID,
 		); err != nil {
 			return nil, err
 		}
@@ -116,3 +429,233 @@ func (q *Queries) GetPosts(ctx context.Context, limit int32) ([]Post, error) {
 	}
 	return items, nil
 }
+
+const getPostsForUserOldestFirst = `-- name: GetPostsForUserOldestFirst :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = $1
+      AND ($2::bigint IS NULL OR feeds_follows.feeds_id = $2)
+      AND ($3::text IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = $3
+      ))
+)
+AND (NOT $4::boolean OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = $1
+))
+AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+AND ($6::timestamp IS NULL OR posts.published_at < $6)
+AND ($7::bigint IS NULL
+    OR (posts.published_at, posts.id) > ($8::timestamp, $7))
+ORDER BY posts.published_at, posts.id
+LIMIT $9
+`
+
+type GetPostsForUserOldestFirstParams struct {
+	UserID           int64
+	FeedID           sql.NullInt64
+	Tag              sql.NullString
+	UnreadOnly       bool
+	Since            sql.NullTime
+	Until            sql.NullTime
+	AfterID          sql.NullInt64
+	AfterPublishedAt sql.NullTime
+	PostLimit        int32
+}
+
+type GetPostsForUserOldestFirstRow struct {
+	ID           int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PublishedAt  time.Time
+	Title        string
+	Description  string
+	Url          string
+	FeedID       int64
+	Content      sql.NullString
+	Author       string
+	Guid         string
+	CanonicalUrl string
+	Fingerprint  string
+	ClusterID    sql.NullInt64
+}
+
+func (q *Queries) GetPostsForUserOldestFirst(ctx context.Context, arg GetPostsForUserOldestFirstParams) ([]GetPostsForUserOldestFirstRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserOldestFirst,
+		arg.UserID,
+		arg.FeedID,
+		arg.Tag,
+		arg.UnreadOnly,
+		arg.Since,
+		arg.Until,
+		arg.AfterID,
+		arg.AfterPublishedAt,
+		arg.PostLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserOldestFirstRow
+	for rows.Next() {
+		var i GetPostsForUserOldestFirstRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishedAt,
+			&i.Title,
+			&i.Description,
+			&i.Url,
+			&i.FeedID,
+			&i.Content,
+			&i.Author,
+			&i.Guid,
+			&i.CanonicalUrl,
+			&i.Fingerprint,
+			&i.ClusterID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reassignFeedPosts = `-- name: ReassignFeedPosts :exec
+UPDATE posts SET feed_id = (
+    SELECT post_sources.feed_id FROM post_sources
+    WHERE post_sources.post_id = posts.id AND post_sources.feed_id <> $1
+    ORDER BY post_sources.created_at
+    LIMIT 1
+)
+WHERE posts.feed_id = $1
+  AND EXISTS (
+      SELECT 1 FROM post_sources
+      WHERE post_sources.post_id = posts.id AND post_sources.feed_id <> $1
+  )
+`
+
+func (q *Queries) ReassignFeedPosts(ctx context.Context, feedID int64) error {
+	_, err := q.db.ExecContext(ctx, reassignFeedPosts, feedID)
+	return err
+}
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id, posts.search_vector,
+    ts_rank(posts.search_vector, search_query)::real AS rank,
+    ts_headline(
+        'english',
+        posts.title || ' ' || posts.description || ' ' || COALESCE(posts.content, ''),
+        search_query,
+        'MaxFragments=2, MinWords=5, MaxWords=20, StartSel=**, StopSel=**'
+    ) AS snippet
+FROM posts, websearch_to_tsquery('english', $1) search_query
+WHERE posts.search_vector @@ search_query
+  AND ($2::bigint IS NULL OR EXISTS (
+      SELECT 1 FROM post_sources
+      WHERE post_sources.post_id = posts.id AND post_sources.feed_id = $2
+  ))
+  AND ($3::timestamp IS NULL OR posts.published_at >= $3)
+  AND ($4::timestamp IS NULL OR posts.published_at < $4)
+  AND (NOT $5::boolean OR EXISTS (
+      SELECT 1 FROM post_sources
+      JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+      WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = $6
+  ))
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT $7
+`
+
+type SearchPostsParams struct {
+	Query        string
+	FeedID       sql.NullInt64
+	Since        sql.NullTime
+	Until        sql.NullTime
+	FollowedOnly bool
+	UserID       int64
+	PostLimit    int32
+}
+
+type SearchPostsRow struct {
+	Post    Post
+	Rank    float32
+	Snippet string
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.Query,
+		arg.FeedID,
+		arg.Since,
+		arg.Until,
+		arg.FollowedOnly,
+		arg.UserID,
+		arg.PostLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.PublishedAt,
+			&i.Post.Title,
+			&i.Post.Description,
+			&i.Post.Url,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.Author,
+			&i.Post.Guid,
+			&i.Post.CanonicalUrl,
+			&i.Post.Fingerprint,
+			&i.Post.ClusterID,
+			&i.Post.SearchVector,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPostClusters = `-- name: SetPostClusters :exec
+UPDATE posts SET cluster_id = item.cluster_id
+FROM unnest(
+    $1::bigint[],
+    $2::bigint[]
+) AS item(post_id, cluster_id)
+WHERE posts.id = item.post_id
+`
+
+type SetPostClustersParams struct {
+	PostIds    []int64
+	ClusterIds []int64
+}
+
+func (q *Queries) SetPostClusters(ctx context.Context, arg SetPostClustersParams) error {
+	_, err := q.db.ExecContext(ctx, setPostClusters, pq.Array(arg.PostIds), pq.Array(arg.ClusterIds))
+	return err
+}
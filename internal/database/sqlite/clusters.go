@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+)
+
+const createCluster = `
+INSERT INTO clusters (created_at) VALUES (?1) RETURNING id
+`
+
+func (q *Queries) CreateCluster(ctx context.Context, createdAt time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createCluster, createdAt)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteEmptyClusters = `
+DELETE FROM clusters
+WHERE NOT EXISTS (SELECT 1 FROM posts WHERE posts.cluster_id = clusters.id)
+`
+
+func (q *Queries) DeleteEmptyClusters(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteEmptyClusters)
+	return err
+}
@@ -0,0 +1,155 @@
+// Package sqlite implements database.Querier on top of SQLite, for
+// single-user installs that do not want to run PostgreSQL. Its queries
+// mirror the ones in sql/queries, rewritten for SQLite; the schema they
+// expect is in sql/sqlite/schema.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+func New(db database.DBTX) *Queries {
+	return &Queries{db: utcDB{db: db}}
+}
+
+type Queries struct {
+	db database.DBTX
+}
+
+var _ database.Querier = (*Queries)(nil)
+
+func (q *Queries) WithTx(tx *sql.Tx) *Queries {
+	return New(tx)
+}
+
+// utcDB converts every time argument to UTC before it reaches the driver.
+// SQLite stores timestamps as text, so they only sort and compare
+// correctly when they all share one offset.
+type utcDB struct {
+	db database.DBTX
+}
+
+func (u utcDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return u.db.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (u utcDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return u.db.PrepareContext(ctx, query)
+}
+
+func (u utcDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return u.db.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (u utcDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return u.db.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+func utcArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = v.UTC()
+		case sql.NullTime:
+			if v.Valid {
+				args[i] = sql.NullTime{Time: v.Time.UTC(), Valid: true}
+			}
+		}
+	}
+	return args
+}
+
+// jsonIDs encodes ids for use with json_each, SQLite's stand-in for
+// PostgreSQL's ANY(array).
+func jsonIDs(ids []int64) (string, error) {
+	if ids == nil {
+		ids = []int64{}
+	}
+	data, err := json.Marshal(ids)
+	return string(data), err
+}
+
+// jsonStrings is jsonIDs for text values.
+func jsonStrings(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanFeed(row scanner) (database.Feed, error) {
+	var i database.Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.FetchFullArticle,
+		&i.LastFetchError,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PollIntervalSeconds,
+		&i.NextFetchAt,
+	)
+	return i, err
+}
+
+func scanFeeds(rows *sql.Rows, err error) ([]database.Feed, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.Feed
+	for rows.Next() {
+		i, err := scanFeed(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// scanPost scans the posts columns followed by extra, the columns a query
+// selects after posts.*. SearchVector is left nil, there is no tsvector
+// in SQLite.
+func scanPost(row scanner, extra ...interface{}) (database.Post, error) {
+	var i database.Post
+	dest := []interface{}{
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishedAt,
+		&i.Title,
+		&i.Description,
+		&i.Url,
+		&i.FeedID,
+		&i.Content,
+		&i.Author,
+		&i.Guid,
+		&i.CanonicalUrl,
+		&i.Fingerprint,
+		&i.ClusterID,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return i, err
+}
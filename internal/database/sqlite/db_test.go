@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+	"github.com/gaba-bouliva/gator/internal/migrate"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newTestQueries returns queries on a fresh in-memory database migrated
+// to the latest schema.
+func newTestQueries(t *testing.T) (*Queries, *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file::memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection to :memory: opens a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := migrate.Load(os.DirFS("../../../sql/sqlite/schema"), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrate.New(db, migrate.SQLite, migrations).Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return New(db), db
+}
+
+// testFeed creates a user following a new feed and returns both.
+func testFeed(t *testing.T, q *Queries, name string) (database.User, database.Feed) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+	user, err := q.CreateUser(ctx, database.CreateUserParams{
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      name,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := q.CreateFeed(ctx, database.CreateFeedParams{
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      name,
+		Url:       "https://" + name + ".example.com/feed.xml",
+		UserID:    sql.NullInt64{Int64: user.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    user.ID,
+		FeedsID:   feed.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user, feed
+}
+
+// testPosts stores a post of feed for each publication date and returns
+// them in the same order.
+func testPosts(t *testing.T, q *Queries, feed database.Feed, publishedAts ...time.Time) []database.CreatePostsRow {
+	t.Helper()
+	params := database.CreatePostsParams{
+		CreatedAt: time.Now(),
+		FeedID:    feed.ID,
+	}
+	for idx, publishedAt := range publishedAts {
+		url := fmt.Sprintf("%s/posts/%d-%d", feed.Url, publishedAt.Unix(), idx)
+		params.PublishedAts = append(params.PublishedAts, publishedAt)
+		params.Titles = append(params.Titles, "Post "+publishedAt.Format(time.RFC3339))
+		params.Descriptions = append(params.Descriptions, "")
+		params.Urls = append(params.Urls, url)
+		params.Contents = append(params.Contents, "")
+		params.Authors = append(params.Authors, "")
+		params.Guids = append(params.Guids, url)
+		params.CanonicalUrls = append(params.CanonicalUrls, url)
+		params.Fingerprints = append(params.Fingerprints, "")
+	}
+	posts, err := q.CreatePosts(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != len(publishedAts) {
+		t.Fatalf("created %d posts, want %d", len(posts), len(publishedAts))
+	}
+
+	sources := database.AddPostSourcesParams{
+		FeedID:    feed.ID,
+		CreatedAt: time.Now(),
+	}
+	for _, post := range posts {
+		sources.PostIds = append(sources.PostIds, post.ID)
+		sources.Urls = append(sources.Urls, post.Url)
+	}
+	if err := q.AddPostSources(context.Background(), sources); err != nil {
+		t.Fatal(err)
+	}
+	return posts
+}
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const createFeedSelector = `
+INSERT INTO feed_selectors (feed_id, created_at, updated_at, item_selector, title_selector, link_selector, date_selector, summary_selector)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+RETURNING feed_id, created_at, updated_at, item_selector, title_selector, link_selector, date_selector, summary_selector
+`
+
+func (q *Queries) CreateFeedSelector(ctx context.Context, arg database.CreateFeedSelectorParams) (database.FeedSelector, error) {
+	row := q.db.QueryRowContext(ctx, createFeedSelector,
+		arg.FeedID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.ItemSelector,
+		arg.TitleSelector,
+		arg.LinkSelector,
+		arg.DateSelector,
+		arg.SummarySelector,
+	)
+	return scanFeedSelector(row)
+}
+
+const getFeedSelector = `
+SELECT feed_id, created_at, updated_at, item_selector, title_selector, link_selector, date_selector, summary_selector FROM feed_selectors WHERE feed_id = ?1
+`
+
+func (q *Queries) GetFeedSelector(ctx context.Context, feedID int64) (database.FeedSelector, error) {
+	return scanFeedSelector(q.db.QueryRowContext(ctx, getFeedSelector, feedID))
+}
+
+func scanFeedSelector(row scanner) (database.FeedSelector, error) {
+	var i database.FeedSelector
+	err := row.Scan(
+		&i.FeedID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ItemSelector,
+		&i.TitleSelector,
+		&i.LinkSelector,
+		&i.DateSelector,
+		&i.SummarySelector,
+	)
+	return i, err
+}
@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const claimFeed = `
+UPDATE feeds
+SET lease_owner = ?1,
+    lease_expires_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now', '+' || ?2 || ' seconds')
+WHERE id = ?3
+  AND (lease_expires_at IS NULL OR julianday(lease_expires_at) < julianday('now'))
+RETURNING *
+`
+
+func (q *Queries) ClaimFeed(ctx context.Context, arg database.ClaimFeedParams) (database.Feed, error) {
+	row := q.db.QueryRowContext(ctx, claimFeed, arg.LeaseOwner, arg.LeaseSeconds, arg.ID)
+	return scanFeed(row)
+}
+
+const claimNextFeed = `
+UPDATE feeds
+SET lease_owner = ?1,
+    lease_expires_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now', '+' || ?2 || ' seconds'),
+    last_fetched_at = ?3,
+    updated_at = ?3
+WHERE id = (
+    SELECT id FROM feeds
+    WHERE (lease_expires_at IS NULL OR julianday(lease_expires_at) < julianday('now'))
+      AND (next_fetch_at IS NULL OR julianday(next_fetch_at) <= julianday('now'))
+    ORDER BY next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST
+    LIMIT 1
+)
+RETURNING *
+`
+
+// ClaimNextFeed needs no row locking: SQLite serializes writers, so the
+// select and update of a single statement cannot interleave with another
+// worker's claim. Leases and schedules are timed by the database clock in
+// UTC, so workers on hosts with skewed clocks or other time zones agree on
+// when a lease expires and a feed is due, and are formatted like the
+// timestamps the driver stores.
+func (q *Queries) ClaimNextFeed(ctx context.Context, arg database.ClaimNextFeedParams) (database.Feed, error) {
+	row := q.db.QueryRowContext(ctx, claimNextFeed, arg.LeaseOwner, arg.LeaseSeconds, arg.ClaimedAt)
+	return scanFeed(row)
+}
+
+const createFeed = `
+INSERT INTO feeds (created_at, updated_at, name, url, user_id)
+VALUES (?1, ?2, ?3, ?4, ?5)
+RETURNING *
+`
+
+func (q *Queries) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	row := q.db.QueryRowContext(ctx, createFeed,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Url,
+		arg.UserID,
+	)
+	return scanFeed(row)
+}
+
+const deleteFeed = `
+DELETE FROM feeds WHERE id = ?1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const getFeedByURL = `
+SELECT * FROM feeds WHERE url = ?1 LIMIT 1
+`
+
+func (q *Queries) GetFeedByURL(ctx context.Context, url string) (database.Feed, error) {
+	return scanFeed(q.db.QueryRowContext(ctx, getFeedByURL, url))
+}
+
+const getFeeds = `
+SELECT * FROM feeds
+`
+
+func (q *Queries) GetFeeds(ctx context.Context) ([]database.Feed, error) {
+	return scanFeeds(q.db.QueryContext(ctx, getFeeds))
+}
+
+const getFeedsByName = `
+SELECT * FROM feeds WHERE name = ?1
+`
+
+func (q *Queries) GetFeedsByName(ctx context.Context, name string) ([]database.Feed, error) {
+	return scanFeeds(q.db.QueryContext(ctx, getFeedsByName, name))
+}
+
+const getFeedsByOwner = `
+SELECT * FROM feeds WHERE user_id = ?1 ORDER BY name
+`
+
+func (q *Queries) GetFeedsByOwner(ctx context.Context, userID sql.NullInt64) ([]database.Feed, error) {
+	return scanFeeds(q.db.QueryContext(ctx, getFeedsByOwner, userID))
+}
+
+const getFollowedFeeds = `
+SELECT feeds.* FROM feeds
+JOIN feeds_follows ON feeds_follows.feeds_id = feeds.id
+WHERE feeds_follows.user_id = ?1
+ORDER BY feeds.name
+`
+
+func (q *Queries) GetFollowedFeeds(ctx context.Context, userID int64) ([]database.Feed, error) {
+	return scanFeeds(q.db.QueryContext(ctx, getFollowedFeeds, userID))
+}
+
+const markFeedFetched = `
+UPDATE feeds SET last_fetched_at = ?1, updated_at = ?2
+WHERE id = ?3
+`
+
+func (q *Queries) MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetched, arg.LastFetchedAt, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const releaseFeedLease = `
+UPDATE feeds SET lease_owner = NULL, lease_expires_at = NULL
+WHERE id = ?1 AND lease_owner = ?2
+`
+
+func (q *Queries) ReleaseFeedLease(ctx context.Context, arg database.ReleaseFeedLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, releaseFeedLease, arg.ID, arg.LeaseOwner)
+	return err
+}
+
+const renameFeed = `
+UPDATE feeds SET name = ?1, updated_at = ?2 WHERE id = ?3
+`
+
+func (q *Queries) RenameFeed(ctx context.Context, arg database.RenameFeedParams) error {
+	_, err := q.db.ExecContext(ctx, renameFeed, arg.Name, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const renewFeedLease = `
+UPDATE feeds SET lease_expires_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now', '+' || ?1 || ' seconds')
+WHERE id = ?2 AND lease_owner = ?3
+`
+
+func (q *Queries) RenewFeedLease(ctx context.Context, arg database.RenewFeedLeaseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renewFeedLease, arg.LeaseSeconds, arg.ID, arg.LeaseOwner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedFetchError = `
+UPDATE feeds SET last_fetch_error = ?1
+WHERE id = ?2
+`
+
+func (q *Queries) SetFeedFetchError(ctx context.Context, arg database.SetFeedFetchErrorParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchError, arg.LastFetchError, arg.ID)
+	return err
+}
+
+const setFeedFetchFullArticle = `
+UPDATE feeds SET fetch_full_article = ?1, updated_at = ?2
+WHERE id = ?3
+`
+
+func (q *Queries) SetFeedFetchFullArticle(ctx context.Context, arg database.SetFeedFetchFullArticleParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchFullArticle, arg.FetchFullArticle, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setFeedOwner = `
+UPDATE feeds SET user_id = ?1, updated_at = ?2 WHERE id = ?3
+`
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg database.SetFeedOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedOwner, arg.UserID, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const setFeedSchedule = `
+UPDATE feeds
+SET poll_interval_seconds = ?1,
+    next_fetch_at = strftime('%Y-%m-%d %H:%M:%f+00:00', 'now', '+' || ?1 || ' seconds')
+WHERE id = ?2
+`
+
+func (q *Queries) SetFeedSchedule(ctx context.Context, arg database.SetFeedScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedSchedule, arg.PollIntervalSeconds, arg.ID)
+	return err
+}
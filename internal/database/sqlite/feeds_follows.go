@@ -0,0 +1,173 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const createFeedFollow = `
+INSERT INTO feeds_follows (created_at, updated_at, user_id, feeds_id)
+VALUES (?1, ?2, ?3, ?4)
+RETURNING id
+`
+
+const getFeedFollowRow = `
+SELECT
+  feeds_follows.id,
+  feeds_follows.created_at,
+  feeds_follows.updated_at,
+  feeds_follows.user_id,
+  feeds_follows.feeds_id,
+  users.name AS user_name,
+  feeds.name AS feed_name
+FROM feeds_follows
+JOIN users ON feeds_follows.user_id = users.id
+JOIN feeds ON feeds_follows.feeds_id = feeds.id
+WHERE feeds_follows.id = ?1
+`
+
+// CreateFeedFollow inserts the follow and then reads it back joined with
+// its user and feed, since SQLite does not allow an INSERT inside a WITH.
+func (q *Queries) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	var i database.CreateFeedFollowRow
+	err := q.db.QueryRowContext(ctx, createFeedFollow,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.FeedsID,
+	).Scan(&i.ID)
+	if err != nil {
+		return i, err
+	}
+	row := q.db.QueryRowContext(ctx, getFeedFollowRow, i.ID)
+	err = row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedsID,
+		&i.UserName,
+		&i.FeedName,
+	)
+	return i, err
+}
+
+const deleteFeedFollow = `
+DELETE FROM feeds_follows WHERE user_id = ?1 AND feeds_id = ?2
+`
+
+func (q *Queries) DeleteFeedFollow(ctx context.Context, arg database.DeleteFeedFollowParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedFollow, arg.UserID, arg.FeedsID)
+	return err
+}
+
+const getFeedFollow = `
+SELECT id, created_at, updated_at, user_id, feeds_id, title FROM feeds_follows WHERE user_id = ?1 AND feeds_id = ?2
+`
+
+func (q *Queries) GetFeedFollow(ctx context.Context, arg database.GetFeedFollowParams) (database.FeedsFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollow, arg.UserID, arg.FeedsID)
+	var i database.FeedsFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedsID,
+		&i.Title,
+	)
+	return i, err
+}
+
+const getFeedFollowerIDs = `
+SELECT user_id FROM feeds_follows WHERE feeds_id = ?1 ORDER BY created_at, id
+`
+
+func (q *Queries) GetFeedFollowerIDs(ctx context.Context, feedsID int64) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowerIDs, feedsID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		items = append(items, userID)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedFollowsForUser = `
+SELECT
+    feeds_follows.id,
+    feeds_follows.created_at,
+    feeds_follows.updated_at,
+    feeds_follows.user_id,
+    feeds_follows.feeds_id,
+    users.name AS user_name,
+    COALESCE(feeds_follows.title, feeds.name) AS feed_name,
+    (
+        SELECT COUNT(*) FROM post_sources
+        WHERE post_sources.feed_id = feeds.id
+          AND NOT EXISTS (
+              SELECT 1 FROM post_reads
+              WHERE post_reads.post_id = post_sources.post_id
+                AND post_reads.user_id = feeds_follows.user_id
+          )
+    ) AS unread_count
+FROM feeds_follows
+JOIN users ON users.id = feeds_follows.user_id
+JOIN feeds ON feeds.id = feeds_follows.feeds_id
+WHERE feeds_follows.user_id = ?1
+`
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID int64) ([]database.GetFeedFollowsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.GetFeedFollowsForUserRow
+	for rows.Next() {
+		var i database.GetFeedFollowsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedsID,
+			&i.UserName,
+			&i.FeedName,
+			&i.UnreadCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setFollowTitle = `
+UPDATE feeds_follows SET title = ?1, updated_at = ?2 WHERE id = ?3
+`
+
+func (q *Queries) SetFollowTitle(ctx context.Context, arg database.SetFollowTitleParams) error {
+	_, err := q.db.ExecContext(ctx, setFollowTitle, arg.Title, arg.UpdatedAt, arg.ID)
+	return err
+}
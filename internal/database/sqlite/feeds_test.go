@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+func TestClaimNextFeedLeases(t *testing.T) {
+	q, _ := newTestQueries(t)
+	ctx := context.Background()
+	_, first := testFeed(t, q, "first")
+	_, second := testFeed(t, q, "second")
+
+	claim := func(owner string) (database.Feed, error) {
+		return q.ClaimNextFeed(ctx, database.ClaimNextFeedParams{
+			LeaseOwner:   sql.NullString{String: owner, Valid: true},
+			LeaseSeconds: 60,
+			ClaimedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+		})
+	}
+
+	a, err := claim("worker-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := claim("worker-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == b.ID {
+		t.Fatalf("both workers claimed feed %d", a.ID)
+	}
+	for _, feed := range []database.Feed{a, b} {
+		if feed.ID != first.ID && feed.ID != second.ID {
+			t.Fatalf("claimed unknown feed %d", feed.ID)
+		}
+	}
+	if _, err := claim("worker-c"); err != sql.ErrNoRows {
+		t.Fatalf("claim with every feed leased: got %v, want sql.ErrNoRows", err)
+	}
+
+	err = q.ReleaseFeedLease(ctx, database.ReleaseFeedLeaseParams{
+		ID:         a.ID,
+		LeaseOwner: sql.NullString{String: "worker-b", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := claim("worker-c"); err != sql.ErrNoRows {
+		t.Fatalf("claim after another worker's release: got %v, want sql.ErrNoRows", err)
+	}
+
+	err = q.ReleaseFeedLease(ctx, database.ReleaseFeedLeaseParams{
+		ID:         a.ID,
+		LeaseOwner: sql.NullString{String: "worker-a", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := claim("worker-c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID != a.ID {
+		t.Fatalf("claimed feed %d, want released feed %d", c.ID, a.ID)
+	}
+}
+
+func TestClaimFeedLeaseExpiry(t *testing.T) {
+	q, db := newTestQueries(t)
+	ctx := context.Background()
+	_, feed := testFeed(t, q, "feed")
+
+	claim := func(owner string) (database.Feed, error) {
+		return q.ClaimFeed(ctx, database.ClaimFeedParams{
+			LeaseOwner:   sql.NullString{String: owner, Valid: true},
+			LeaseSeconds: 60,
+			ID:           feed.ID,
+		})
+	}
+
+	claimed, err := claim("refresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimed.LeaseOwner.String != "refresh" || !claimed.LeaseExpiresAt.Valid {
+		t.Fatalf("claimed feed has lease %v until %v", claimed.LeaseOwner, claimed.LeaseExpiresAt)
+	}
+	if _, err := claim("agg"); err != sql.ErrNoRows {
+		t.Fatalf("claim of a leased feed: got %v, want sql.ErrNoRows", err)
+	}
+
+	renewed, err := q.RenewFeedLease(ctx, database.RenewFeedLeaseParams{
+		LeaseSeconds: 60,
+		ID:           feed.ID,
+		LeaseOwner:   sql.NullString{String: "agg", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed != 0 {
+		t.Fatal("a worker renewed a lease it does not hold")
+	}
+	renewed, err = q.RenewFeedLease(ctx, database.RenewFeedLeaseParams{
+		LeaseSeconds: 60,
+		ID:           feed.ID,
+		LeaseOwner:   sql.NullString{String: "refresh", Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed != 1 {
+		t.Fatal("the lease holder could not renew its lease")
+	}
+
+	_, err = db.Exec("UPDATE feeds SET lease_expires_at = '2000-01-01 00:00:00+00:00' WHERE id = ?", feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimed, err = claim("agg")
+	if err != nil {
+		t.Fatalf("claim of a feed whose lease expired: %v", err)
+	}
+	if claimed.LeaseOwner.String != "agg" {
+		t.Fatalf("expired lease claimed by %q, want agg", claimed.LeaseOwner.String)
+	}
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const createFilter = `
+INSERT INTO filters (created_at, updated_at, user_id, field, kind, pattern)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+RETURNING id, created_at, updated_at, user_id, field, kind, pattern
+`
+
+func (q *Queries) CreateFilter(ctx context.Context, arg database.CreateFilterParams) (database.Filter, error) {
+	row := q.db.QueryRowContext(ctx, createFilter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Field,
+		arg.Kind,
+		arg.Pattern,
+	)
+	return scanFilter(row)
+}
+
+const deleteFilter = `
+DELETE FROM filters WHERE id = ?1 AND user_id = ?2
+`
+
+func (q *Queries) DeleteFilter(ctx context.Context, arg database.DeleteFilterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFilter, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFiltersForUser = `
+SELECT id, created_at, updated_at, user_id, field, kind, pattern FROM filters WHERE user_id = ?1 ORDER BY id
+`
+
+func (q *Queries) GetFiltersForUser(ctx context.Context, userID int64) ([]database.Filter, error) {
+	rows, err := q.db.QueryContext(ctx, getFiltersForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.Filter
+	for rows.Next() {
+		i, err := scanFilter(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanFilter(row scanner) (database.Filter, error) {
+	var i database.Filter
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Field,
+		&i.Kind,
+		&i.Pattern,
+	)
+	return i, err
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const addFollowTag = `
+INSERT INTO follow_tags (follow_id, tag, created_at)
+VALUES (?1, ?2, ?3)
+ON CONFLICT (follow_id, tag) DO NOTHING
+`
+
+func (q *Queries) AddFollowTag(ctx context.Context, arg database.AddFollowTagParams) error {
+	_, err := q.db.ExecContext(ctx, addFollowTag, arg.FollowID, arg.Tag, arg.CreatedAt)
+	return err
+}
+
+const getFollowTagsForUser = `
+SELECT follow_tags.follow_id, follow_tags.tag
+FROM follow_tags
+JOIN feeds_follows ON feeds_follows.id = follow_tags.follow_id
+WHERE feeds_follows.user_id = ?1
+ORDER BY follow_tags.tag
+`
+
+func (q *Queries) GetFollowTagsForUser(ctx context.Context, userID int64) ([]database.GetFollowTagsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowTagsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.GetFollowTagsForUserRow
+	for rows.Next() {
+		var i database.GetFollowTagsForUserRow
+		if err := rows.Scan(&i.FollowID, &i.Tag); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeFollowTag = `
+DELETE FROM follow_tags WHERE follow_id = ?1 AND tag = ?2
+`
+
+func (q *Queries) RemoveFollowTag(ctx context.Context, arg database.RemoveFollowTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeFollowTag, arg.FollowID, arg.Tag)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
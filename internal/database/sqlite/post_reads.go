@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const markPostRead = `
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT ?1, posts.id, ?2
+FROM posts
+WHERE posts.id = ?3
+ON CONFLICT (user_id, post_id) DO UPDATE SET read_at = excluded.read_at
+`
+
+func (q *Queries) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPostRead, arg.UserID, arg.ReadAt, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markPostUnread = `
+DELETE FROM post_reads WHERE user_id = ?1 AND post_id = ?2
+`
+
+func (q *Queries) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPostUnread, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markPostsRead = `
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT DISTINCT ?1, posts.id, ?2
+FROM posts
+JOIN post_sources ON post_sources.post_id = posts.id
+JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+WHERE feeds_follows.user_id = ?1
+  AND (?3 IS NULL OR post_sources.feed_id = ?3)
+  AND (?4 IS NULL OR posts.published_at < ?4)
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+func (q *Queries) MarkPostsRead(ctx context.Context, arg database.MarkPostsReadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markPostsRead,
+		arg.UserID,
+		arg.ReadAt,
+		arg.FeedID,
+		arg.Before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const createPostSignature = `
+INSERT INTO post_signatures (post_id, published_at, minhash)
+VALUES (?1, ?2, ?3)
+`
+
+func (q *Queries) CreatePostSignatures(ctx context.Context, arg database.CreatePostSignaturesParams) error {
+	for idx, postID := range arg.PostIds {
+		_, err := q.db.ExecContext(ctx, createPostSignature, postID, arg.PublishedAts[idx], arg.Minhashes[idx])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const getClusterCandidates = `
+SELECT post_signatures.post_id, post_signatures.published_at, posts.cluster_id, post_signatures.minhash
+FROM post_signatures
+JOIN posts ON posts.id = post_signatures.post_id
+WHERE post_signatures.published_at BETWEEN ?1 AND ?2
+`
+
+func (q *Queries) GetClusterCandidates(ctx context.Context, arg database.GetClusterCandidatesParams) ([]database.GetClusterCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClusterCandidates, arg.WindowStart, arg.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.GetClusterCandidatesRow
+	for rows.Next() {
+		var i database.GetClusterCandidatesRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.PublishedAt,
+			&i.ClusterID,
+			&i.Minhash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const addPostSource = `
+INSERT INTO post_sources (post_id, feed_id, created_at, url)
+VALUES (?1, ?2, ?3, ?4)
+ON CONFLICT (post_id, feed_id) DO NOTHING
+`
+
+func (q *Queries) AddPostSources(ctx context.Context, arg database.AddPostSourcesParams) error {
+	for idx, postID := range arg.PostIds {
+		_, err := q.db.ExecContext(ctx, addPostSource,
+			postID,
+			arg.FeedID,
+			arg.CreatedAt,
+			arg.Urls[idx],
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const getPostSources = `
+SELECT
+    post_sources.post_id,
+    post_sources.feed_id,
+    COALESCE(feeds_follows.title, feeds.name) AS feed_name
+FROM post_sources
+JOIN feeds ON feeds.id = post_sources.feed_id
+LEFT JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    AND feeds_follows.user_id = ?1
+WHERE post_sources.post_id IN (SELECT value FROM json_each(?2))
+ORDER BY post_sources.created_at
+`
+
+func (q *Queries) GetPostSources(ctx context.Context, arg database.GetPostSourcesParams) ([]database.GetPostSourcesRow, error) {
+	ids, err := jsonIDs(arg.PostIds)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.db.QueryContext(ctx, getPostSources, arg.UserID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.GetPostSourcesRow
+	for rows.Next() {
+		var i database.GetPostSourcesRow
+		if err := rows.Scan(&i.PostID, &i.FeedID, &i.FeedName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
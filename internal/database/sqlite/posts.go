@@ -0,0 +1,346 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const createPost = `
+INSERT INTO posts (created_at, updated_at, published_at, title, description, url, feed_id, content, author, guid, canonical_url, fingerprint)
+VALUES (?1, ?1, ?2, ?3, ?4, ?5, ?6, NULLIF(?7, ''), ?8, ?9, ?10, ?11)
+ON CONFLICT (url) DO NOTHING
+RETURNING id, published_at, title, description, url
+`
+
+// CreatePosts inserts the posts one statement at a time, as SQLite has no
+// array parameters and its queries do not cost a network round trip.
+func (q *Queries) CreatePosts(ctx context.Context, arg database.CreatePostsParams) ([]database.CreatePostsRow, error) {
+	var items []database.CreatePostsRow
+	for idx := range arg.Urls {
+		var i database.CreatePostsRow
+		err := q.db.QueryRowContext(ctx, createPost,
+			arg.CreatedAt,
+			arg.PublishedAts[idx],
+			arg.Titles[idx],
+			arg.Descriptions[idx],
+			arg.Urls[idx],
+			arg.FeedID,
+			arg.Contents[idx],
+			arg.Authors[idx],
+			arg.Guids[idx],
+			arg.CanonicalUrls[idx],
+			arg.Fingerprints[idx],
+		).Scan(
+			&i.ID,
+			&i.PublishedAt,
+			&i.Title,
+			&i.Description,
+			&i.Url,
+		)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, nil
+}
+
+const deleteFeedPosts = `
+DELETE FROM posts WHERE feed_id = ?1
+`
+
+func (q *Queries) DeleteFeedPosts(ctx context.Context, feedID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedPosts, feedID)
+	return err
+}
+
+const findDuplicatePosts = `
+SELECT id, url, canonical_url, guid, fingerprint FROM posts
+WHERE url IN (SELECT value FROM json_each(?1))
+   OR canonical_url IN (SELECT value FROM json_each(?2))
+   OR (guid <> '' AND guid IN (SELECT value FROM json_each(?3)))
+   OR (fingerprint <> '' AND fingerprint IN (SELECT value FROM json_each(?4)))
+`
+
+func (q *Queries) FindDuplicatePosts(ctx context.Context, arg database.FindDuplicatePostsParams) ([]database.FindDuplicatePostsRow, error) {
+	var args []interface{}
+	for _, values := range [][]string{arg.Urls, arg.CanonicalUrls, arg.Guids, arg.Fingerprints} {
+		encoded, err := jsonStrings(values)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, encoded)
+	}
+	rows, err := q.db.QueryContext(ctx, findDuplicatePosts, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.FindDuplicatePostsRow
+	for rows.Next() {
+		var i database.FindDuplicatePostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.CanonicalUrl,
+			&i.Guid,
+			&i.Fingerprint,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getClusterMembersForUser = `
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE posts.cluster_id IN (SELECT value FROM json_each(?1))
+AND EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = ?2
+      AND (?3 IS NULL OR feeds_follows.feeds_id = ?3)
+      AND (?4 IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = ?4
+      ))
+)
+AND (NOT ?5 OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = ?2
+))
+AND (?6 IS NULL OR posts.published_at >= ?6)
+AND (?7 IS NULL OR posts.published_at < ?7)
+`
+
+func (q *Queries) GetClusterMembersForUser(ctx context.Context, arg database.GetClusterMembersForUserParams) ([]database.GetClusterMembersForUserRow, error) {
+	clusterIDs, err := jsonIDs(arg.ClusterIds)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := scanTimelinePosts(q.db.QueryContext(ctx, getClusterMembersForUser,
+		clusterIDs,
+		arg.UserID,
+		arg.FeedID,
+		arg.Tag,
+		arg.UnreadOnly,
+		arg.Since,
+		arg.Until,
+	))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]database.GetClusterMembersForUserRow, 0, len(posts))
+	for _, post := range posts {
+		items = append(items, database.GetClusterMembersForUserRow(post))
+	}
+	return items, nil
+}
+
+const getFeedPublishTimes = `
+SELECT posts.published_at FROM post_sources
+JOIN posts ON posts.id = post_sources.post_id
+WHERE post_sources.feed_id = ?1
+ORDER BY posts.published_at DESC
+LIMIT ?2
+`
+
+func (q *Queries) GetFeedPublishTimes(ctx context.Context, arg database.GetFeedPublishTimesParams) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedPublishTimes, arg.FeedID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var publishedAt time.Time
+		if err := rows.Scan(&publishedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, publishedAt)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPost = `
+SELECT * FROM posts WHERE id = ?1
+`
+
+func (q *Queries) GetPost(ctx context.Context, id int64) (database.Post, error) {
+	return scanPost(q.db.QueryRowContext(ctx, getPost, id))
+}
+
+const getPostsForUser = `
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = ?1
+      AND (?2 IS NULL OR feeds_follows.feeds_id = ?2)
+      AND (?3 IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = ?3
+      ))
+)
+AND (NOT ?4 OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = ?1
+))
+AND (?5 IS NULL OR posts.published_at >= ?5)
+AND (?6 IS NULL OR posts.published_at < ?6)
+AND (?7 IS NULL
+    OR (posts.published_at, posts.id) < (?8, ?7))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT ?9
+`
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	return scanTimelinePosts(q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.FeedID,
+		arg.Tag,
+		arg.UnreadOnly,
+		arg.Since,
+		arg.Until,
+		arg.AfterID,
+		arg.AfterPublishedAt,
+		arg.PostLimit,
+	))
+}
+
+const getPostsForUserOldestFirst = `
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = ?1
+      AND (?2 IS NULL OR feeds_follows.feeds_id = ?2)
+      AND (?3 IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = ?3
+      ))
+)
+AND (NOT ?4 OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = ?1
+))
+AND (?5 IS NULL OR posts.published_at >= ?5)
+AND (?6 IS NULL OR posts.published_at < ?6)
+AND (?7 IS NULL
+    OR (posts.published_at, posts.id) > (?8, ?7))
+ORDER BY posts.published_at, posts.id
+LIMIT ?9
+`
+
+func (q *Queries) GetPostsForUserOldestFirst(ctx context.Context, arg database.GetPostsForUserOldestFirstParams) ([]database.GetPostsForUserOldestFirstRow, error) {
+	posts, err := scanTimelinePosts(q.db.QueryContext(ctx, getPostsForUserOldestFirst,
+		arg.UserID,
+		arg.FeedID,
+		arg.Tag,
+		arg.UnreadOnly,
+		arg.Since,
+		arg.Until,
+		arg.AfterID,
+		arg.AfterPublishedAt,
+		arg.PostLimit,
+	))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]database.GetPostsForUserOldestFirstRow, 0, len(posts))
+	for _, post := range posts {
+		items = append(items, database.GetPostsForUserOldestFirstRow(post))
+	}
+	return items, nil
+}
+
+// scanTimelinePosts scans the posts columns the timeline queries select,
+// which share their row type.
+func scanTimelinePosts(rows *sql.Rows, err error) ([]database.GetPostsForUserRow, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.GetPostsForUserRow
+	for rows.Next() {
+		var i database.GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishedAt,
+			&i.Title,
+			&i.Description,
+			&i.Url,
+			&i.FeedID,
+			&i.Content,
+			&i.Author,
+			&i.Guid,
+			&i.CanonicalUrl,
+			&i.Fingerprint,
+			&i.ClusterID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reassignFeedPosts = `
+UPDATE posts SET feed_id = (
+    SELECT post_sources.feed_id FROM post_sources
+    WHERE post_sources.post_id = posts.id AND post_sources.feed_id <> ?1
+    ORDER BY post_sources.created_at
+    LIMIT 1
+)
+WHERE posts.feed_id = ?1
+  AND EXISTS (
+      SELECT 1 FROM post_sources
+      WHERE post_sources.post_id = posts.id AND post_sources.feed_id <> ?1
+  )
+`
+
+func (q *Queries) ReassignFeedPosts(ctx context.Context, feedID int64) error {
+	_, err := q.db.ExecContext(ctx, reassignFeedPosts, feedID)
+	return err
+}
+
+const setPostCluster = `
+UPDATE posts SET cluster_id = ?1 WHERE id = ?2
+`
+
+func (q *Queries) SetPostClusters(ctx context.Context, arg database.SetPostClustersParams) error {
+	for idx, postID := range arg.PostIds {
+		_, err := q.db.ExecContext(ctx, setPostCluster, arg.ClusterIds[idx], postID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+func TestCreatePostsSkipsStoredURLs(t *testing.T) {
+	q, _ := newTestQueries(t)
+	ctx := context.Background()
+	_, feed := testFeed(t, q, "feed")
+	published := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+
+	batch := func(urls ...string) database.CreatePostsParams {
+		params := database.CreatePostsParams{
+			CreatedAt: time.Now(),
+			FeedID:    feed.ID,
+		}
+		for _, url := range urls {
+			params.PublishedAts = append(params.PublishedAts, published)
+			params.Titles = append(params.Titles, url)
+			params.Descriptions = append(params.Descriptions, "")
+			params.Urls = append(params.Urls, url)
+			params.Contents = append(params.Contents, "")
+			params.Authors = append(params.Authors, "")
+			params.Guids = append(params.Guids, "guid:"+url)
+			params.CanonicalUrls = append(params.CanonicalUrls, url)
+			params.Fingerprints = append(params.Fingerprints, "")
+		}
+		return params
+	}
+
+	created, err := q.CreatePosts(ctx, batch("https://a.example.com", "https://b.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created %d posts, want 2", len(created))
+	}
+
+	created, err = q.CreatePosts(ctx, batch("https://b.example.com", "https://c.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0].Url != "https://c.example.com" {
+		t.Fatalf("second batch created %+v, want only https://c.example.com", created)
+	}
+
+	duplicates, err := q.FindDuplicatePosts(ctx, database.FindDuplicatePostsParams{
+		Urls:          []string{"https://elsewhere.example.com"},
+		CanonicalUrls: []string{"https://elsewhere.example.com"},
+		Guids:         []string{"guid:https://a.example.com"},
+		Fingerprints:  []string{""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates) != 1 || duplicates[0].Url != "https://a.example.com" {
+		t.Fatalf("duplicates by guid: got %+v, want https://a.example.com", duplicates)
+	}
+}
+
+func TestGetPostsForUserPages(t *testing.T) {
+	q, _ := newTestQueries(t)
+	ctx := context.Background()
+	user, feed := testFeed(t, q, "feed")
+	day := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	// two posts share a publication date, so pages must break ties by id
+	posts := testPosts(t, q, feed,
+		day,
+		day.Add(time.Hour),
+		day.Add(time.Hour),
+		day.Add(2*time.Hour),
+		day.Add(3*time.Hour),
+	)
+	newestFirst := []int64{posts[4].ID, posts[3].ID, posts[2].ID, posts[1].ID, posts[0].ID}
+	if posts[1].ID > posts[2].ID {
+		newestFirst[2], newestFirst[3] = newestFirst[3], newestFirst[2]
+	}
+
+	params := database.GetPostsForUserParams{
+		UserID:    user.ID,
+		PostLimit: 2,
+	}
+	var got []int64
+	for {
+		page, err := q.GetPostsForUser(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, post := range page {
+			got = append(got, post.ID)
+		}
+		last := page[len(page)-1]
+		params.AfterID = sql.NullInt64{Int64: last.ID, Valid: true}
+		params.AfterPublishedAt = sql.NullTime{Time: last.PublishedAt, Valid: true}
+	}
+	if !reflect.DeepEqual(got, newestFirst) {
+		t.Fatalf("newest first pages: got %v, want %v", got, newestFirst)
+	}
+
+	oldest := database.GetPostsForUserOldestFirstParams{
+		UserID:    user.ID,
+		PostLimit: 2,
+	}
+	got = nil
+	for {
+		page, err := q.GetPostsForUserOldestFirst(ctx, oldest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, post := range page {
+			got = append(got, post.ID)
+		}
+		last := page[len(page)-1]
+		oldest.AfterID = sql.NullInt64{Int64: last.ID, Valid: true}
+		oldest.AfterPublishedAt = sql.NullTime{Time: last.PublishedAt, Valid: true}
+	}
+	for i, j := 0, len(newestFirst)-1; i < j; i, j = i+1, j-1 {
+		newestFirst[i], newestFirst[j] = newestFirst[j], newestFirst[i]
+	}
+	if !reflect.DeepEqual(got, newestFirst) {
+		t.Fatalf("oldest first pages: got %v, want %v", got, newestFirst)
+	}
+}
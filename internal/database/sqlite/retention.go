@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const deletePosts = `
+DELETE FROM posts
+WHERE id IN (SELECT value FROM json_each(?1))
+  AND NOT EXISTS (SELECT 1 FROM stars WHERE stars.post_id = posts.id)
+`
+
+func (q *Queries) DeletePosts(ctx context.Context, ids []int64) (int64, error) {
+	encoded, err := jsonIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+	result, err := q.db.ExecContext(ctx, deletePosts, encoded)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFeedRetention = `
+SELECT feed_id, updated_at, max_age_seconds, max_posts FROM feed_retention WHERE feed_id = ?1
+`
+
+func (q *Queries) GetFeedRetention(ctx context.Context, feedID int64) (database.FeedRetention, error) {
+	row := q.db.QueryRowContext(ctx, getFeedRetention, feedID)
+	var i database.FeedRetention
+	err := row.Scan(
+		&i.FeedID,
+		&i.UpdatedAt,
+		&i.MaxAgeSeconds,
+		&i.MaxPosts,
+	)
+	return i, err
+}
+
+// getPrunablePostIDs compares ages with julianday because the cutoff is
+// computed in SQL and would not share the stored timestamps' text format.
+const getPrunablePostIDs = `
+WITH ranked AS (
+    SELECT posts.id, posts.feed_id, posts.published_at,
+        ROW_NUMBER() OVER (PARTITION BY posts.feed_id ORDER BY posts.published_at DESC) AS position
+    FROM posts
+)
+SELECT ranked.id FROM ranked
+LEFT JOIN feed_retention ON feed_retention.feed_id = ranked.feed_id
+WHERE NOT EXISTS (SELECT 1 FROM stars WHERE stars.post_id = ranked.id)
+  AND (
+      julianday(ranked.published_at) < julianday(?1) - COALESCE(feed_retention.max_age_seconds, ?2) / 86400.0
+      OR ranked.position > COALESCE(feed_retention.max_posts, ?3)
+  )
+  AND ranked.id > ?4
+ORDER BY ranked.id
+LIMIT ?5
+`
+
+func (q *Queries) GetPrunablePostIDs(ctx context.Context, arg database.GetPrunablePostIDsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getPrunablePostIDs,
+		arg.Now,
+		arg.DefaultMaxAgeSeconds,
+		arg.DefaultMaxPosts,
+		arg.AfterID,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setFeedRetention = `
+INSERT INTO feed_retention (feed_id, updated_at, max_age_seconds, max_posts)
+VALUES (?1, ?2, ?3, ?4)
+ON CONFLICT (feed_id) DO UPDATE
+SET updated_at = excluded.updated_at,
+    max_age_seconds = excluded.max_age_seconds,
+    max_posts = excluded.max_posts
+`
+
+func (q *Queries) SetFeedRetention(ctx context.Context, arg database.SetFeedRetentionParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedRetention,
+		arg.FeedID,
+		arg.UpdatedAt,
+		arg.MaxAgeSeconds,
+		arg.MaxPosts,
+	)
+	return err
+}
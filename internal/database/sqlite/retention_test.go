@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+func TestPrunePostsKeepsNewestAndStarred(t *testing.T) {
+	q, _ := newTestQueries(t)
+	ctx := context.Background()
+	user, feed := testFeed(t, q, "feed")
+	now := time.Now().UTC()
+	posts := testPosts(t, q, feed,
+		now.Add(-4*time.Hour),
+		now.Add(-3*time.Hour),
+		now.Add(-2*time.Hour),
+		now.Add(-time.Hour),
+	)
+
+	_, err := q.StarPost(ctx, database.StarPostParams{
+		UserID:    user.ID,
+		CreatedAt: now,
+		PostID:    posts[0].ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := q.GetPrunablePostIDs(ctx, database.GetPrunablePostIDsParams{
+		Now:             now,
+		DefaultMaxPosts: sql.NullInt32{Int32: 2, Valid: true},
+		BatchSize:       10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{posts[1].ID}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("prunable posts: got %v, want %v", ids, want)
+	}
+
+	deleted, err := q.DeletePosts(ctx, []int64{posts[0].ID, posts[1].ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("deleted %d posts, want 1 as starred posts are kept", deleted)
+	}
+	if _, err := q.GetPost(ctx, posts[0].ID); err != nil {
+		t.Fatalf("starred post: %v", err)
+	}
+	if _, err := q.GetPost(ctx, posts[1].ID); err != sql.ErrNoRows {
+		t.Fatalf("pruned post: got %v, want sql.ErrNoRows", err)
+	}
+
+	ids, err = q.GetPrunablePostIDs(ctx, database.GetPrunablePostIDsParams{
+		Now:                  now,
+		DefaultMaxAgeSeconds: sql.NullInt32{Int32: int32((90 * time.Minute).Seconds()), Valid: true},
+		BatchSize:            10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{posts[2].ID}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("posts older than the max age: got %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// snippetWords is how many words of a matching post SearchPosts returns as
+// its snippet, like MaxWords in the PostgreSQL ts_headline call.
+const snippetWords = 20
+
+const searchPosts = `
+SELECT posts.*, %s AS rank, snippet(posts_search, '**', '**', '...', -1, %d) AS snippet
+FROM posts_search
+JOIN posts ON posts.id = posts_search.docid
+WHERE posts_search MATCH ?7
+  AND (?1 IS NULL OR EXISTS (
+      SELECT 1 FROM post_sources
+      WHERE post_sources.post_id = posts.id AND post_sources.feed_id = ?1
+  ))
+  AND (?2 IS NULL OR posts.published_at >= ?2)
+  AND (?3 IS NULL OR posts.published_at < ?3)
+  AND (NOT ?4 OR EXISTS (
+      SELECT 1 FROM post_sources
+      JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+      WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = ?5
+  ))
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT ?6
+`
+
+// SearchPosts matches the query against the posts_search FTS4 index, which
+// stems English words like the PostgreSQL search vector does. The query
+// takes the same "quoted phrases", OR and -excluded words as
+// websearch_to_tsquery. FTS4 has no ranking function, so matches in the
+// title rank above matches in the description, which rank above the
+// article text.
+func (q *Queries) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	match, terms := matchQuery(arg.Query)
+	if match == "" {
+		return nil, nil
+	}
+
+	args := []interface{}{
+		arg.FeedID,
+		arg.Since,
+		arg.Until,
+		arg.FollowedOnly,
+		arg.UserID,
+		arg.PostLimit,
+		match,
+	}
+	var rank []string
+	for _, term := range terms {
+		args = append(args, term)
+		rank = append(rank, fmt.Sprintf(
+			"(instr(lower(posts.title), ?%[1]d) > 0) * 1.0 + (instr(lower(posts.description), ?%[1]d) > 0) * 0.4 + (instr(lower(COALESCE(posts.content, '')), ?%[1]d) > 0) * 0.2",
+			len(args),
+		))
+	}
+	query := fmt.Sprintf(searchPosts, strings.Join(rank, " + "), snippetWords)
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.SearchPostsRow
+	for rows.Next() {
+		var i database.SearchPostsRow
+		i.Post, err = scanPost(rows, &i.Rank, &i.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// matchQuery translates a web-search style query into an FTS4 MATCH
+// expression, returning it along with the lowercased words and phrases it
+// looks for. Words and phrases are ANDed, OR separates alternatives and
+// words prefixed with - are excluded from the alternative they are in,
+// which is how websearch_to_tsquery reads them. An alternative that only
+// excludes words is dropped, as FTS4 cannot match it.
+func matchQuery(query string) (string, []string) {
+	var alternatives, terms []string
+	var include, exclude []string
+	flush := func() {
+		if len(include) > 0 {
+			alternative := strings.Join(include, " ")
+			for _, term := range exclude {
+				alternative += " NOT " + term
+			}
+			alternatives = append(alternatives, "("+alternative+")")
+		}
+		include, exclude = nil, nil
+	}
+
+	for {
+		query = strings.TrimSpace(query)
+		if query == "" {
+			break
+		}
+		negate := strings.HasPrefix(query, "-")
+		if negate {
+			query = query[1:]
+		}
+
+		var term string
+		quoted := strings.HasPrefix(query, `"`)
+		if quoted {
+			end := strings.Index(query[1:], `"`)
+			if end < 0 {
+				term, query = query[1:], ""
+			} else {
+				term, query = query[1:end+1], query[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(query, unicode.IsSpace)
+			if end < 0 {
+				term, query = query, ""
+			} else {
+				term, query = query[:end], query[end:]
+			}
+		}
+
+		term = strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(term, `"`, " ")), " "))
+		switch {
+		case term == "":
+		case term == "or" && !quoted && !negate:
+			flush()
+		case negate:
+			exclude = append(exclude, `"`+term+`"`)
+		default:
+			include = append(include, `"`+term+`"`)
+			terms = append(terms, term)
+		}
+	}
+	flush()
+	return strings.Join(alternatives, " OR "), terms
+}
@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		match string
+		terms []string
+	}{
+		{`Go generics`, `("go" "generics")`, []string{"go", "generics"}},
+		{`"Go  1.30" release`, `("go 1.30" "release")`, []string{"go 1.30", "release"}},
+		{`go OR rust`, `("go") OR ("rust")`, []string{"go", "rust"}},
+		{`go generics or rust -memory`, `("go" "generics") OR ("rust" NOT "memory")`, []string{"go", "generics", "rust"}},
+		{`"or" -"segmentation fault"`, `("or" NOT "segmentation fault")`, []string{"or"}},
+		{`-crab OR stars`, `("stars")`, []string{"stars"}},
+		{`OR`, ``, nil},
+		{`col:umn "quote`, `("col:umn" "quote")`, []string{"col:umn", "quote"}},
+	}
+	for _, test := range tests {
+		match, terms := matchQuery(test.query)
+		if match != test.match || !reflect.DeepEqual(terms, test.terms) {
+			t.Errorf("matchQuery(%q) = %q, %q; want %q, %q", test.query, match, terms, test.match, test.terms)
+		}
+	}
+}
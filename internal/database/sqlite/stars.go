@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const countFeedStars = `
+SELECT COUNT(*) FROM stars
+JOIN posts ON posts.id = stars.post_id
+WHERE posts.feed_id = ?1
+`
+
+func (q *Queries) CountFeedStars(ctx context.Context, feedID int64) (int64, error) {
+	var count int64
+	err := q.db.QueryRowContext(ctx, countFeedStars, feedID).Scan(&count)
+	return count, err
+}
+
+const deleteFeedStars = `
+DELETE FROM stars
+WHERE post_id IN (SELECT id FROM posts WHERE feed_id = ?1)
+`
+
+func (q *Queries) DeleteFeedStars(ctx context.Context, feedID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedStars, feedID)
+	return err
+}
+
+const getStarredPosts = `
+SELECT posts.*, stars.note, stars.created_at AS starred_at
+FROM stars
+JOIN posts ON posts.id = stars.post_id
+WHERE stars.user_id = ?1
+ORDER BY stars.created_at DESC
+`
+
+func (q *Queries) GetStarredPosts(ctx context.Context, userID int64) ([]database.GetStarredPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStarredPosts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.GetStarredPostsRow
+	for rows.Next() {
+		var i database.GetStarredPostsRow
+		i.Post, err = scanPost(rows, &i.Note, &i.StarredAt)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const starPost = `
+INSERT INTO stars (user_id, post_id, created_at, note)
+SELECT ?1, posts.id, ?2, ?3
+FROM posts
+WHERE posts.id = ?4
+ON CONFLICT (user_id, post_id) DO UPDATE SET note = COALESCE(NULLIF(excluded.note, ''), stars.note)
+`
+
+func (q *Queries) StarPost(ctx context.Context, arg database.StarPostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, starPost,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.Note,
+		arg.PostID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unstarPost = `
+DELETE FROM stars WHERE user_id = ?1 AND post_id = ?2
+`
+
+func (q *Queries) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unstarPost, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
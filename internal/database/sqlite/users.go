@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const createUser = `
+INSERT INTO users (created_at, updated_at, name, is_admin)
+VALUES (?1, ?2, ?3, NOT EXISTS (SELECT 1 FROM users))
+RETURNING id, created_at, updated_at, name, is_admin
+`
+
+func (q *Queries) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	row := q.db.QueryRowContext(ctx, createUser, arg.CreatedAt, arg.UpdatedAt, arg.Name)
+	return scanUser(row)
+}
+
+const deleteAllUsers = `
+DELETE FROM users
+`
+
+func (q *Queries) DeleteAllUsers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteAllUsers)
+	return err
+}
+
+const deleteUser = `
+DELETE FROM users WHERE id = ?1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const getUserByID = `
+SELECT id, created_at, updated_at, name, is_admin FROM users WHERE id = ?1 LIMIT 1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id int64) (database.User, error) {
+	return scanUser(q.db.QueryRowContext(ctx, getUserByID, id))
+}
+
+const getUserByName = `
+SELECT id, created_at, updated_at, name, is_admin FROM users WHERE name = ?1 LIMIT 1
+`
+
+func (q *Queries) GetUserByName(ctx context.Context, name string) (database.User, error) {
+	return scanUser(q.db.QueryRowContext(ctx, getUserByName, name))
+}
+
+const getUsers = `
+SELECT id, created_at, updated_at, name, is_admin FROM users
+`
+
+func (q *Queries) GetUsers(ctx context.Context) ([]database.User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []database.User
+	for rows.Next() {
+		i, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAdmins = `
+SELECT id FROM users WHERE is_admin ORDER BY id
+`
+
+// LockAdmins cannot lock rows in SQLite; a transaction that reads the
+// admins and then writes fails to commit if another one changed them in
+// between, as SQLite serializes writers.
+func (q *Queries) LockAdmins(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, lockAdmins)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// LockUserRegistration needs no lock in SQLite: CreateUser checks whether it
+// creates the first user in the same statement that inserts it, and SQLite
+// serializes writing statements.
+func (q *Queries) LockUserRegistration(ctx context.Context) error {
+	return nil
+}
+
+const setUserAdmin = `
+UPDATE users SET is_admin = ?1, updated_at = ?2 WHERE id = ?3
+`
+
+func (q *Queries) SetUserAdmin(ctx context.Context, arg database.SetUserAdminParams) error {
+	_, err := q.db.ExecContext(ctx, setUserAdmin, arg.IsAdmin, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+func scanUser(row scanner) (database.User, error) {
+	var i database.User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.IsAdmin,
+	)
+	return i, err
+}
@@ -0,0 +1,333 @@
+// Package migrate applies the goose-style migrations embedded in gator.
+// Versions are recorded in goose's goose_db_version table, so databases
+// migrated with the goose CLI and with gator can be used interchangeably.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dialect holds the SQL that differs between the supported databases.
+type Dialect struct {
+	createTable string
+	insert      string
+	delete      string
+}
+
+var Postgres = Dialect{
+	createTable: `CREATE TABLE IF NOT EXISTS goose_db_version (
+    id serial NOT NULL,
+    version_id bigint NOT NULL,
+    is_applied boolean NOT NULL,
+    tstamp timestamp NULL default now(),
+    PRIMARY KEY(id)
+)`,
+	insert: "INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, $2)",
+	delete: "DELETE FROM goose_db_version WHERE version_id = $1",
+}
+
+var SQLite = Dialect{
+	createTable: `CREATE TABLE IF NOT EXISTS goose_db_version (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    version_id INTEGER NOT NULL,
+    is_applied INTEGER NOT NULL,
+    tstamp TIMESTAMP DEFAULT (datetime('now'))
+)`,
+	insert: "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?)",
+	delete: "DELETE FROM goose_db_version WHERE version_id = ?",
+}
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// NoTransaction is set by goose's "-- +goose NO TRANSACTION"
+	// annotation, for statements that cannot run inside a transaction,
+	// such as SQLite's foreign_keys pragma.
+	NoTransaction bool
+}
+
+// Status is a migration along with when it was applied, if it was.
+type Status struct {
+	Migration
+	AppliedAt sql.NullTime
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+func New(db *sql.DB, dialect Dialect, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}
+}
+
+// Load reads the migrations in dir, named like goose migrations
+// (001_users.sql), sorted by version.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must start with a version", entry.Name())
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with a version", entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, err := parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+		migration.Version = version
+		migration.Name = entry.Name()
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrations %s and %s share a version", migrations[i-1].Name, migrations[i].Name)
+		}
+	}
+	return migrations, nil
+}
+
+// parse splits a migration into the statements under its
+// "-- +goose Up" and "-- +goose Down" annotations.
+func parse(data string) (Migration, error) {
+	var migration Migration
+	var up, down strings.Builder
+	var section *strings.Builder
+	for _, line := range strings.SplitAfter(data, "\n") {
+		annotation, ok := strings.CutPrefix(strings.TrimSpace(line), "-- +goose ")
+		if !ok {
+			if section != nil {
+				section.WriteString(line)
+			}
+			continue
+		}
+		switch strings.TrimSpace(annotation) {
+		case "Up":
+			section = &up
+		case "Down":
+			section = &down
+		case "NO TRANSACTION":
+			migration.NoTransaction = true
+		}
+	}
+	if strings.TrimSpace(up.String()) == "" {
+		return Migration{}, fmt.Errorf("missing -- +goose Up section")
+	}
+	migration.Up = up.String()
+	migration.Down = down.String()
+	return migration, nil
+}
+
+// Latest returns the version of the newest migration.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the newest version applied to the database, 0 when no
+// migration has been applied yet.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var version int64
+	for v := range applied {
+		version = max(version, v)
+	}
+	return version, nil
+}
+
+// Check returns an error unless the database schema is exactly at the
+// latest migration.
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case version == 0:
+		return fmt.Errorf("database has no schema, run gator migrate up")
+	case version < m.Latest():
+		return fmt.Errorf("database schema is at version %d but gator needs %d, run gator migrate up", version, m.Latest())
+	case version > m.Latest():
+		return fmt.Errorf("database schema version %d is newer than this gator supports (%d), upgrade gator", version, m.Latest())
+	}
+	return nil
+}
+
+// Status lists every migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, Status{
+			Migration: migration,
+			AppliedAt: applied[migration.Version],
+		})
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration in order, each in its own
+// transaction, and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, migration, migration.Up, m.dialect.insert, migration.Version, true)
+		if err != nil {
+			return done, fmt.Errorf("applying %s: %w", migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the newest applied migration and returns it.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	if version == 0 {
+		return Migration{}, fmt.Errorf("no migrations to roll back")
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version != version {
+			continue
+		}
+		err := m.run(ctx, migration, migration.Down, m.dialect.delete, migration.Version)
+		if err != nil {
+			return migration, fmt.Errorf("rolling back %s: %w", migration.Name, err)
+		}
+		return migration, nil
+	}
+	return Migration{}, fmt.Errorf("database version %d has no matching migration", version)
+}
+
+// run executes statements of migration and records the change in
+// goose_db_version in one transaction, unless the migration opted out of
+// transactions.
+func (m *Migrator) run(ctx context.Context, migration Migration, statements, record string, args ...interface{}) error {
+	if migration.NoTransaction {
+		if strings.TrimSpace(statements) != "" {
+			if _, err := m.db.ExecContext(ctx, statements); err != nil {
+				return err
+			}
+		}
+		_, err := m.db.ExecContext(ctx, record, args...)
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(statements) != "" {
+		if _, err := tx.ExecContext(ctx, statements); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applied returns when each applied version was applied, creating the
+// version table first if needed. Like goose, only the newest row of each
+// version counts.
+func (m *Migrator) applied(ctx context.Context) (map[int64]sql.NullTime, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version_id, is_applied, tstamp FROM goose_db_version ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool)
+	applied := make(map[int64]sql.NullTime)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var tstamp sql.NullTime
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied && version > 0 {
+			applied[version] = tstamp
+		}
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	var count int
+	err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM goose_db_version").Scan(&count)
+	if err == nil {
+		return nil
+	}
+
+	if _, err := m.db.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return err
+	}
+	_, err = m.db.ExecContext(ctx, m.dialect.insert, 0, true)
+	return err
+}
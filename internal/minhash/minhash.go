@@ -0,0 +1,119 @@
+package minhash
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	// NumHashes is the length of a signature. The similarity estimate has a
+	// standard error of roughly 1/sqrt(NumHashes).
+	NumHashes = 64
+	// ShingleSize is the number of consecutive words in a shingle.
+	ShingleSize = 2
+)
+
+var tags = regexp.MustCompile(`<[^>]*>`)
+
+// Signature is a MinHash signature of a text's word shingles.
+type Signature []uint64
+
+// Shingles splits text into lower-cased words, ignoring HTML tags and
+// punctuation, and hashes every run of ShingleSize consecutive words.
+func Shingles(text string) map[uint64]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(tags.ReplaceAllString(text, " ")), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	shingles := make(map[uint64]struct{})
+	if len(words) == 0 {
+		return shingles
+	}
+	size := min(ShingleSize, len(words))
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		shingles[h.Sum64()] = struct{}{}
+	}
+	return shingles
+}
+
+// Compute returns the MinHash signature of text, or nil when text has no
+// words.
+func Compute(text string) Signature {
+	shingles := Shingles(text)
+	if len(shingles) == 0 {
+		return nil
+	}
+
+	sig := make(Signature, NumHashes)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for shingle := range shingles {
+		for i := range sig {
+			if h := mix(shingle ^ seeds[i]); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the shingle sets behind
+// two signatures.
+func Similarity(a, b Signature) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+// Encode serializes a signature for storage.
+func (s Signature) Encode() []byte {
+	buf := make([]byte, 8*len(s))
+	for i, v := range s {
+		binary.LittleEndian.PutUint64(buf[8*i:], v)
+	}
+	return buf
+}
+
+// Decode parses a signature produced by Encode.
+func Decode(buf []byte) Signature {
+	sig := make(Signature, len(buf)/8)
+	for i := range sig {
+		sig[i] = binary.LittleEndian.Uint64(buf[8*i:])
+	}
+	return sig
+}
+
+var seeds = func() [NumHashes]uint64 {
+	var s [NumHashes]uint64
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range s {
+		x = mix(x + uint64(i))
+		s[i] = x
+	}
+	return s
+}()
+
+// mix is the splitmix64 finalizer, used to derive NumHashes independent
+// hash functions from a single shingle hash.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,222 @@
+package readability
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	positiveHints = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text`)
+	negativeHints = regexp.MustCompile(`(?i)banner|comment|combx|footer|menu|meta|nav|promo|related|share|shoutbox|sidebar|social|sponsor|widget|ad-|ads`)
+	whitespace    = regexp.MustCompile(`\s+`)
+)
+
+// tags that never hold article content and are dropped before scoring
+var skipTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+	atom.Button:   true,
+}
+
+// tags whose text is kept as a separate paragraph in the output
+var blockTags = map[atom.Atom]bool{
+	atom.P:          true,
+	atom.Pre:        true,
+	atom.Blockquote: true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.Li:         true,
+}
+
+const minParagraphLen = 25
+
+// Extract parses an HTML document and returns the plain text of its main
+// content, using the paragraph scoring approach of readability.js.
+func Extract(r io.Reader) (string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", err
+	}
+
+	scores := make(map[*html.Node]float64)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && skipTags[n.DataAtom] {
+			return
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.P || n.DataAtom == atom.Pre) {
+			scoreParagraph(n, scores)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var best *html.Node
+	var bestScore float64
+	for n, score := range scores {
+		score *= 1 - linkDensity(n)
+		if best == nil || score > bestScore {
+			best = n
+			bestScore = score
+		}
+	}
+	if best == nil {
+		best = findFirst(doc, atom.Article)
+	}
+	if best == nil {
+		best = findFirst(doc, atom.Body)
+	}
+	if best == nil {
+		return "", fmt.Errorf("no content found in document")
+	}
+
+	var paragraphs []string
+	collectText(best, &paragraphs)
+	content := strings.Join(paragraphs, "\n\n")
+	if content == "" {
+		return "", fmt.Errorf("no content found in document")
+	}
+	return content, nil
+}
+
+func scoreParagraph(p *html.Node, scores map[*html.Node]float64) {
+	text := nodeText(p)
+	if len(text) < minParagraphLen {
+		return
+	}
+	score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+
+	parent := p.Parent
+	if parent == nil {
+		return
+	}
+	if _, ok := scores[parent]; !ok {
+		scores[parent] = classWeight(parent)
+	}
+	scores[parent] += score
+
+	grandparent := parent.Parent
+	if grandparent == nil || grandparent.Type != html.ElementNode {
+		return
+	}
+	if _, ok := scores[grandparent]; !ok {
+		scores[grandparent] = classWeight(grandparent)
+	}
+	scores[grandparent] += score / 2
+}
+
+func classWeight(n *html.Node) float64 {
+	var weight float64
+	for _, attr := range n.Attr {
+		if attr.Key != "class" && attr.Key != "id" {
+			continue
+		}
+		if negativeHints.MatchString(attr.Val) {
+			weight -= 25
+		}
+		if positiveHints.MatchString(attr.Val) {
+			weight += 25
+		}
+	}
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		weight += 10
+	case atom.Div:
+		weight += 5
+	}
+	return weight
+}
+
+func linkDensity(n *html.Node) float64 {
+	total := len(nodeText(n))
+	if total == 0 {
+		return 0
+	}
+	var linked int
+	var walk func(c *html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			linked += len(nodeText(c))
+			return
+		}
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			walk(cc)
+		}
+	}
+	walk(n)
+	return float64(linked) / float64(total)
+}
+
+func collectText(n *html.Node, paragraphs *[]string) {
+	if n.Type == html.ElementNode && skipTags[n.DataAtom] {
+		return
+	}
+	if n.Type == html.ElementNode && blockTags[n.DataAtom] {
+		if text := nodeText(n); text != "" {
+			*paragraphs = append(*paragraphs, text)
+		}
+		return
+	}
+	if n.Type == html.TextNode {
+		if text := normalize(n.Data); text != "" {
+			*paragraphs = append(*paragraphs, text)
+		}
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		collectText(c, paragraphs)
+	}
+}
+
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(c *html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode && skipTags[c.DataAtom] {
+			return
+		}
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+			sb.WriteString(" ")
+		}
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			walk(cc)
+		}
+	}
+	walk(n)
+	return normalize(sb.String())
+}
+
+func normalize(s string) string {
+	return strings.TrimSpace(whitespace.ReplaceAllString(s, " "))
+}
+
+func findFirst(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findFirst(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
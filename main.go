@@ -3,26 +3,45 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/xml"
+	"errors"
 	"fmt"
-	"html"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gaba-bouliva/gator/internal/application"
 	"github.com/gaba-bouliva/gator/internal/config"
 	"github.com/gaba-bouliva/gator/internal/database"
-	"github.com/google/uuid"
 
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-var app = application.NewApp(nil)
+var app = application.NewApp(nil, nil, nil)
+
+const (
+	// feedFetchTimeout bounds a single feed download. A refresh can take
+	// much longer when full articles are downloaded, so the lease is
+	// renewed every feedLeaseRenewInterval while the worker holds it.
+	feedFetchTimeout       = time.Minute
+	feedLeaseDuration      = 5 * time.Minute
+	feedLeaseRenewInterval = time.Minute
+)
+
+// workerID identifies this process in feed leases.
+var workerID = func() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}()
 
 func main() {
 	cfg, err := config.Read()
@@ -31,25 +50,50 @@ func main() {
 		log.Fatalln(err)
 	}
 	app.Config = cfg
-	db, err := sql.Open("postgres", app.Config.DBUrl)
+	store, err := openDatabase(app.Config.DBUrl)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer db.Close()
+	defer store.db.Close()
 
-	app = application.NewApp(db)
+	app = application.NewApp(store.db, store.queries, store.withTx)
+	app.Config = cfg
+	app.Migrator = store.migrator
 
 	app.RegisterCMD("login", handleLogin)
 	app.RegisterCMD("register", handleRegister)
 	app.RegisterCMD("reset", handleReset)
+	app.RegisterCMD("migrate", handleMigrate)
 	app.RegisterCMD("users", middlewareLoggedIn(handleUsers))
 	app.RegisterCMD("agg", middlewareLoggedIn(handleAgg))
 	app.RegisterCMD("addfeed", middlewareLoggedIn(handleAddFeed))
+	app.RegisterCMD("addscraped", middlewareLoggedIn(handleAddScrapedFeed))
 	app.RegisterCMD("feeds", middlewareLoggedIn(handleFeeds))
 	app.RegisterCMD("follow", middlewareLoggedIn(handleFollow))
 	app.RegisterCMD("following", middlewareLoggedIn(handleFollowing))
 	app.RegisterCMD("unfollow", middlewareLoggedIn(unfollow))
+	app.RegisterCMD("tag", middlewareLoggedIn(handleTag))
+	app.RegisterCMD("untag", middlewareLoggedIn(handleUntag))
+	app.RegisterCMD("title", middlewareLoggedIn(handleTitle))
 	app.RegisterCMD("browse", middlewareLoggedIn(handleBrowse))
+	app.RegisterCMD("search", middlewareLoggedIn(handleSearch))
+	app.RegisterCMD("read", middlewareLoggedIn(handleRead))
+	app.RegisterCMD("unread", middlewareLoggedIn(handleUnread))
+	app.RegisterCMD("markread", middlewareLoggedIn(handleMarkRead))
+	app.RegisterCMD("star", middlewareLoggedIn(handleStar))
+	app.RegisterCMD("unstar", middlewareLoggedIn(handleUnstar))
+	app.RegisterCMD("starred", middlewareLoggedIn(handleStarred))
+	app.RegisterCMD("refresh", middlewareLoggedIn(handleRefresh))
+	app.RegisterCMD("ingest", middlewareLoggedIn(handleIngest))
+	app.RegisterCMD("filter", middlewareLoggedIn(handleFilter))
+	app.RegisterCMD("fullarticle", middlewareLoggedIn(handleFullArticle))
+	app.RegisterCMD("retention", middlewareLoggedIn(handleRetention))
+	app.RegisterCMD("prune", middlewareLoggedIn(handlePrune))
+	app.RegisterCMD("removefeed", middlewareLoggedIn(handleRemoveFeed))
+	app.RegisterCMD("renamefeed", middlewareLoggedIn(handleRenameFeed))
+	app.RegisterCMD("transferfeed", middlewareLoggedIn(handleTransferFeed))
+	app.RegisterCMD("deleteuser", middlewareLoggedIn(handleDeleteUser))
+	app.RegisterCMD("admin", middlewareLoggedIn(handleAdmin))
 
 	args := os.Args
 
@@ -66,6 +110,15 @@ func main() {
 		Arguments: cmdArgs,
 	}
 
+	// every command but migrate needs the schema this build was written for
+	if newCmd.Name != "migrate" {
+		err = app.Migrator.Check(context.Background())
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
 	err = app.RunCMD(newCmd)
 	if err != nil {
 		fmt.Println(err)
@@ -73,72 +126,248 @@ func main() {
 	}
 }
 
+// scrapeFeeds claims the feed that has waited longest and refreshes it.
+// The claim is a lease held in the feeds table, so several agg workers can
+// share one database without fetching the same feed; a lease left behind
+// by a crashed worker expires after feedLeaseDuration. Lease expiry is
+// computed by the database, so it does not depend on the workers' clocks.
 func scrapeFeeds(a *application.App) error {
-	nextFeed, err := a.DB.GetNextFeedToFetch(context.Background())
+	owner := sql.NullString{String: workerID, Valid: true}
+	nextFeed, err := a.DB.ClaimNextFeed(context.Background(), database.ClaimNextFeedParams{
+		LeaseOwner:   owner,
+		LeaseSeconds: int32(feedLeaseDuration / time.Second),
+		ClaimedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+	})
 	if err != nil {
 		return err
 	}
+	inserted, skipped, err := refreshLeasedFeed(a, nextFeed, owner)
+	if err == nil {
+		fmt.Printf("%s: %d new post(s), %d skipped\n", nextFeed.Name, inserted, skipped)
+	}
+	return err
+}
+
+// errFeedLeased is returned by refreshClaimedFeed when another worker is
+// fetching the feed.
+var errFeedLeased = errors.New("feed is being fetched by another worker")
+
+// refreshClaimedFeed claims the lease on feed and refreshes it, so that a
+// feed refreshed by hand is not fetched by an agg worker at the same time.
+func refreshClaimedFeed(a *application.App, feed database.Feed) (int, int, error) {
+	owner := sql.NullString{String: workerID, Valid: true}
+	feed, err := a.DB.ClaimFeed(context.Background(), database.ClaimFeedParams{
+		LeaseOwner:   owner,
+		LeaseSeconds: int32(feedLeaseDuration / time.Second),
+		ID:           feed.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, errFeedLeased
+		}
+		return 0, 0, err
+	}
+	return refreshLeasedFeed(a, feed, owner)
+}
+
+// refreshLeasedFeed refreshes feed, whose lease owner holds, renewing the
+// lease meanwhile, and releases it afterwards.
+func refreshLeasedFeed(a *application.App, feed database.Feed, owner sql.NullString) (int, int, error) {
+	stopRenewing := renewFeedLease(a, feed, owner)
+	inserted, skipped, err := refreshFeed(a, feed)
+	stopRenewing()
+
+	releaseErr := a.DB.ReleaseFeedLease(context.Background(), database.ReleaseFeedLeaseParams{
+		ID:         feed.ID,
+		LeaseOwner: owner,
+	})
+	if err == nil {
+		err = releaseErr
+	}
+	return inserted, skipped, err
+}
+
+// renewFeedLease extends the lease owner holds on feed every
+// feedLeaseRenewInterval until the returned function is called, so a slow
+// refresh is not claimed by another worker halfway through.
+func renewFeedLease(a *application.App, feed database.Feed, owner sql.NullString) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(feedLeaseRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				renewed, err := a.DB.RenewFeedLease(context.Background(), database.RenewFeedLeaseParams{
+					LeaseSeconds: int32(feedLeaseDuration / time.Second),
+					ID:           feed.ID,
+					LeaseOwner:   owner,
+				})
+				if err != nil {
+					log.Printf("renewing lease on %s: %v", feed.Name, err)
+				} else if renewed == 0 {
+					log.Printf("lost lease on %s", feed.Name)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// refreshFeed fetches feed once, records the outcome on the feed and saves
+// its new items, returning how many items were inserted and skipped.
+func refreshFeed(a *application.App, feed database.Feed) (int, int, error) {
 	markFeedFetchedParams := database.MarkFeedFetchedParams{
 		LastFetchedAt: sql.NullTime{Time: time.Now(), Valid: true},
 		UpdatedAt:     time.Now(),
-		ID:            nextFeed.ID,
+		ID:            feed.ID,
 	}
-	err = a.DB.MarkFeedFetched(context.Background(), markFeedFetchedParams)
+	err := a.DB.MarkFeedFetched(context.Background(), markFeedFetchedParams)
 	if err != nil {
-		return err
+		return 0, 0, err
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), feedFetchTimeout)
+	defer cancelFunc()
+	rssFeed, fetchErr := fetchSource(ctx, a, feed)
+	if fetchErr != nil {
+		// a failing feed keeps its schedule so it is retried at its
+		// usual interval rather than on every tick
+		err = scheduleNextFetch(a, feed)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	err = a.DB.SetFeedFetchError(context.Background(), database.SetFeedFetchErrorParams{
+		LastFetchError: errorStatus(fetchErr),
+		ID:             feed.ID,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if fetchErr != nil {
+		return 0, 0, &feedError{Feed: feed, Err: fetchErr}
+	}
+
+	inserted, skipped, err := savePosts(a, feed, rssFeed)
+	if err != nil {
+		// like a failed fetch, a feed whose posts cannot be saved waits
+		// for its next scheduled fetch instead of being retried every tick
+		if scheduleErr := scheduleNextFetch(a, feed); scheduleErr != nil {
+			return 0, 0, scheduleErr
+		}
+		return 0, 0, err
 	}
-	rssFeed, err := fetchFeed(context.Background(), nextFeed.Url)
+	return inserted, skipped, scheduleNextFetch(a, feed)
+}
+
+// savePosts stores the items of rssFeed as posts of feed in one
+// transaction, with a fixed number of queries however long the feed is.
+// Items already stored from this or another feed are recorded as an extra
+// source of the existing post instead of being inserted twice. It returns
+// how many items were inserted as new posts and how many were skipped.
+func savePosts(a *application.App, feed database.Feed, rssFeed *RSSFeed) (int, int, error) {
+	items := newPostItems(rssFeed)
+
+	// full articles are downloaded first so the transaction is not held
+	// open across network requests
+	articles, err := fetchArticles(a, feed, items)
 	if err != nil {
+		return 0, 0, err
+	}
+
+	inserted := 0
+	err = a.InTx(context.Background(), func(q database.Querier) error {
+		inserted, err = insertPosts(q, feed, items, articles)
 		return err
+	})
+	if err != nil {
+		return 0, 0, err
 	}
+	return inserted, len(rssFeed.Channel.Items) - inserted, nil
+}
 
-	for _, item := range rssFeed.Channel.Items {
-		fmt.Println(item.Title)
-		_, err := a.DB.GetPostByUrl(context.Background(), item.Link)
-		if err == nil {
+// insertPosts inserts the items that are not stored yet in one batch and
+// records feed as a source of both the new and the already stored posts.
+// Items repeated within the batch are only inserted once.
+func insertPosts(q database.Querier, feed database.Feed, items []postItem, articles map[string]sql.NullString) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	stored, err := findDuplicates(q, items)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	posts := database.CreatePostsParams{CreatedAt: now, FeedID: feed.ID}
+	sources := database.AddPostSourcesParams{CreatedAt: now, FeedID: feed.ID}
+	batch := make(map[string]int64)
+	for _, item := range items {
+		if id, ok := item.duplicateOf(stored); ok {
+			sources.PostIds = append(sources.PostIds, id)
+			sources.Urls = append(sources.Urls, item.Link)
 			continue
 		}
-		pubDate, err := tryParseDate(item.PubDate)
-		if err != nil {
-			return err
+		if _, ok := item.duplicateOf(batch); ok {
+			continue
 		}
-		createdPostParams := database.CreatePostParams{
-			ID:          int32(uuid.New().ID()),
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-			PublishedAt: pubDate,
-			Title:       item.Title,
-			Description: item.Description,
-			Url:         item.Link,
-			FeedID:      nextFeed.ID,
+		for _, key := range item.keys() {
+			batch[key] = 0
 		}
-		_, err = a.DB.CreatePost(context.Background(), createdPostParams)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
 
-func handleBrowse(a *application.App, cmd application.Command, user database.User) error {
-	limit := 2
-	if len(cmd.Arguments) > 0 {
-		parseIntArg, err := strconv.Atoi(cmd.Arguments[0])
+		posts.PublishedAts = append(posts.PublishedAts, item.publishedAt)
+		posts.Titles = append(posts.Titles, item.Title)
+		posts.Descriptions = append(posts.Descriptions, item.Description)
+		posts.Urls = append(posts.Urls, item.Link)
+		posts.Contents = append(posts.Contents, articles[item.Link].String)
+		posts.Authors = append(posts.Authors, item.Author)
+		posts.Guids = append(posts.Guids, item.guid)
+		posts.CanonicalUrls = append(posts.CanonicalUrls, item.canonicalURL)
+		posts.Fingerprints = append(posts.Fingerprints, item.fingerprint)
+	}
+
+	var created []database.CreatePostsRow
+	if len(posts.Urls) > 0 {
+		// an item whose url was stored by a concurrent writer since
+		// findDuplicates ran is skipped rather than failing the batch
+		created, err = q.CreatePosts(context.Background(), posts)
 		if err != nil {
-			return err
+			return 0, err
 		}
-		limit = parseIntArg
 	}
-	posts, err := a.DB.GetPosts(context.Background(), int32(limit))
+	sort.Slice(created, func(i, j int) bool {
+		return created[i].ID < created[j].ID
+	})
+	for _, post := range created {
+		sources.PostIds = append(sources.PostIds, post.ID)
+		sources.Urls = append(sources.Urls, post.Url)
+	}
+
+	err = clusterPosts(q, created)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	for _, post := range posts {
-		fmt.Printf("* %+v", post)
-		fmt.Println()
+	if len(sources.PostIds) > 0 {
+		err = q.AddPostSources(context.Background(), sources)
+		if err != nil {
+			return 0, err
+		}
 	}
-	return nil
+	return len(created), nil
+}
+
+// printPost prints the post id, title, publication date and url; the id is
+// what read, star and the other post commands expect.
+func printPost(post database.Post) {
+	fmt.Printf("* [%d] %s\n", post.ID, post.Title)
+	fmt.Printf("  %s | %s\n", post.PublishedAt.Format("2006-01-02 15:04"), post.Url)
 }
 
 func tryParseDate(dateStr string) (time.Time, error) {
@@ -150,11 +379,18 @@ func tryParseDate(dateStr string) (time.Time, error) {
 		time.RFC822,
 		time.RFC822Z,
 		time.RFC850,
-		"2006-01-02 15:04:05",  // Example: 2025-02-02 14:30:00
-		"2006-01-02T15:04:05Z", // Example: 2025-02-02T14:30:00Z
-		"02 Jan 2006",          // Example: 02 Feb 2025
-		"2006-01-02",           // Example: 2025-02-02
-		"02/01/2006",           // Example: 02/02/2025
+		"2006-01-02 15:04:05",     // Example: 2025-02-02 14:30:00
+		"2006-01-02T15:04:05Z",    // Example: 2025-02-02T14:30:00Z
+		"02 Jan 2006",             // Example: 02 Feb 2025
+		"2006-01-02",              // Example: 2025-02-02
+		"02/01/2006",              // Example: 02/02/2025
+		"January 2, 2006",         // Example: February 2, 2025
+		"Jan 2, 2006",             // Example: Feb 2, 2025
+		"Monday, January 2, 2006", // Example: Sunday, February 2, 2025
+		"Mon, Jan 2, 2006",        // Example: Sun, Feb 2, 2025
+		"January 2 2006",          // Example: February 2 2025
+		"2 January 2006",          // Example: 2 February 2025
+		"2 Jan 2006",              // Example: 2 Feb 2025
 	}
 
 	for _, format := range formats {
@@ -174,8 +410,12 @@ func handleFollowing(a *application.App, cmd application.Command, user database.
 		return err
 	}
 
+	if len(cmd.Arguments) > 0 && cmd.Arguments[0] == "--by-tag" {
+		return printFollowingByTag(a, user, usrFeedFollowings)
+	}
+
 	for _, feedsFollow := range usrFeedFollowings {
-		fmt.Println("feed name: ", feedsFollow.FeedName)
+		fmt.Printf("feed name:  %s (%d unread)\n", feedsFollow.FeedName, feedsFollow.UnreadCount)
 	}
 
 	return nil
@@ -198,7 +438,6 @@ func handleFollow(a *application.App, cmd application.Command, user database.Use
 	}
 
 	createdFeedFollowParam := database.CreateFeedFollowParams{
-		ID:        int32(uuid.New().ID()),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		UserID:    user.ID,
@@ -248,10 +487,28 @@ func handleFeeds(a *application.App, cmd application.Command, user database.User
 	if err != nil {
 		return err
 	}
+	users, err := a.DB.GetUsers(context.Background())
+	if err != nil {
+		return err
+	}
+	owners := make(map[int64]string, len(users))
+	for _, u := range users {
+		owners[u.ID] = u.Name
+	}
 	for _, feed := range feeds {
 		fmt.Println("* ", feed.Name)
 		fmt.Println("* ", feed.Url)
-		fmt.Println("* ", user.Name)
+		if feed.UserID.Valid {
+			fmt.Println("* ", owners[feed.UserID.Int64])
+		} else {
+			fmt.Println("*  (no owner)")
+		}
+		if feed.PollIntervalSeconds.Valid {
+			fmt.Println("* polled every: ", time.Duration(feed.PollIntervalSeconds.Int32)*time.Second)
+		}
+		if feed.LastFetchError.Valid {
+			fmt.Println("* last fetch error: ", feed.LastFetchError.String)
+		}
 	}
 
 	return nil
@@ -268,35 +525,48 @@ func handleAddFeed(a *application.App, cmd application.Command, user database.Us
 	// if err != nil {
 	// 	return err
 	// }
+	var createdFeed database.Feed
+	err = a.InTx(context.Background(), func(q database.Querier) error {
+		createdFeed, err = addFeed(q, user, cmd.Arguments[0], cmd.Arguments[1])
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%+v\n", createdFeed)
+
+	return nil
+}
+
+// addFeed creates a feed owned by user and follows it on their behalf.
+// Callers run it in a transaction so a failed follow leaves no feed behind.
+func addFeed(q database.Querier, user database.User, name, url string) (database.Feed, error) {
 	createFeedParams := database.CreateFeedParams{
-		ID:        int32(uuid.New().ID()),
-		Name:      cmd.Arguments[0],
-		Url:       cmd.Arguments[1],
-		UserID:    user.ID,
+		Name:      name,
+		Url:       url,
+		UserID:    sql.NullInt64{Int64: user.ID, Valid: true},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	createdFeed, err := a.DB.CreateFeed(context.Background(), createFeedParams)
+	createdFeed, err := q.CreateFeed(context.Background(), createFeedParams)
 	if err != nil {
-		return err
+		return database.Feed{}, err
 	}
 
 	createdFeedFollowParam := database.CreateFeedFollowParams{
-		ID:        int32(uuid.New().ID()),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		UserID:    user.ID,
 		FeedsID:   createdFeed.ID,
 	}
-	_, err = a.DB.CreateFeedFollow(context.Background(), createdFeedFollowParam)
+	_, err = q.CreateFeedFollow(context.Background(), createdFeedFollowParam)
 	if err != nil {
 		fmt.Println("error creating associated feed_follow")
-		return err
+		return database.Feed{}, err
 	}
 
-	fmt.Printf("%+v\n", createdFeed)
-
-	return nil
+	return createdFeed, nil
 }
 
 // func getfeed(url string) (*RSSFeed, error) {
@@ -318,24 +588,102 @@ func handleAgg(a *application.App, cmd application.Command, user database.User)
 	if err != nil {
 		return err
 	}
+	if reqWaitTime <= 0 {
+		return fmt.Errorf("%s command expects a positive duration", cmd.Name)
+	}
+
+	pidFile, err := a.Config.PidFilePath()
+	if err != nil {
+		return err
+	}
+	release, err := acquirePidFile(pidFile)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	fmt.Println("Collecting feeds every ", reqWaitTime)
 	ticker := time.NewTicker(reqWaitTime)
 	defer ticker.Stop()
-	for range ticker.C {
+	summary := newAggSummary()
+	defer summary.log()
+	var backoff time.Duration
+	var lastPrune time.Time
+	for {
+		// scrapeFeeds is not given ctx so a fetch that is already running
+		// finishes and its posts are saved before shutting down.
 		err := scrapeFeeds(a)
-		if err != nil {
-			return err
+		kind := classifyAggError(err)
+		wait := ticker.C
+		switch kind {
+		case aggOK, aggNoFeeds:
+			// no feed being due is the normal idle state between fetches
+			backoff = 0
+		case aggTransientDB:
+			backoff = nextBackoff(backoff)
+			log.Printf("database unavailable, retrying in %s: %v", backoff, err)
+			wait = time.After(backoff)
+		default:
+			backoff = 0
+			log.Printf("%s: %v", kind, err)
+		}
+		summary.record(kind)
+		if a.Config.AutoPrune && kind != aggTransientDB && time.Since(lastPrune) >= autoPruneInterval {
+			lastPrune = time.Now()
+			pruned, err := prunePosts(a, false)
+			if err != nil {
+				log.Printf("pruning posts: %v", err)
+			} else if pruned > 0 {
+				log.Printf("pruned %d post(s)", pruned)
+			}
+		}
+		if summary.due() {
+			summary.log()
+			summary.reset()
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("received shutdown signal, stopping aggregation")
+			return nil
+		case <-wait:
 		}
 	}
+}
 
-	ctx, cancelFunc := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancelFunc()
-	rssFeed, err := fetchFeed(ctx, "https://www.wagslane.dev/index.xml")
-	if err != nil {
-		return err
+// fetchSource fetches the items of feed, scraping the page with the feed's
+// CSS selectors when it has any, reading file:// urls from disk, running
+// exec: commands and downloading the document otherwise.
+func fetchSource(ctx context.Context, a *application.App, feed database.Feed) (*RSSFeed, error) {
+	selectors, err := a.DB.GetFeedSelector(ctx, feed.ID)
+	if err == nil {
+		return fetchHTMLFeed(ctx, feed.Url, selectors)
 	}
-	fmt.Printf("Fetched Feed: %+v\n", rssFeed)
-	return nil
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+	if strings.HasPrefix(feed.Url, "file://") {
+		return readFileFeed(feed.Url)
+	}
+	if strings.HasPrefix(feed.Url, "exec:") {
+		if !a.Config.AllowExecFeeds {
+			return nil, fmt.Errorf("exec feeds are disabled, set allow_exec_feeds in ~/.gatorconfig.json to run them")
+		}
+		return runExecFeed(ctx, strings.TrimPrefix(feed.Url, "exec:"))
+	}
+	return fetchFeed(ctx, feed.Url)
+}
+
+// errorStatus converts a fetch error into the value stored in
+// feeds.last_fetch_error, clearing it when the fetch succeeded.
+func errorStatus(err error) sql.NullString {
+	if err == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: err.Error(), Valid: true}
 }
 
 func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
@@ -354,28 +702,14 @@ func fetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 
 	resData, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	var rssFeed RSSFeed
-
-	err = xml.Unmarshal(resData, &rssFeed)
-	if err != nil {
-		return nil, err
-	}
-
-	rssFeed.Channel.Title = html.UnescapeString(rssFeed.Channel.Title)
-	rssFeed.Channel.Description = html.UnescapeString(rssFeed.Channel.Description)
-
-	for _, item := range rssFeed.Channel.Items {
-		item.Title = html.UnescapeString(item.Title)
-		item.Description = html.UnescapeString(item.Description)
-	}
-
-	return &rssFeed, nil
+	return parseFeed(resData)
 }
 
 func handleLogin(a *application.App, cmd application.Command) error {
@@ -407,12 +741,21 @@ func handleRegister(a *application.App, cmd application.Command) error {
 	}
 
 	createUserParams := database.CreateUserParams{
-		ID:        int32(uuid.New().ID()),
 		Name:      cmd.Arguments[0],
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	newUser, err := a.DB.CreateUser(context.Background(), createUserParams)
+	// the first user becomes an admin; registrations are serialized so two
+	// users registering at once cannot both be first
+	var newUser database.User
+	err = a.InTx(context.Background(), func(q database.Querier) error {
+		err := q.LockUserRegistration(context.Background())
+		if err != nil {
+			return err
+		}
+		newUser, err = q.CreateUser(context.Background(), createUserParams)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -446,11 +789,14 @@ func handleUsers(a *application.App, cmd application.Command, user database.User
 		return err
 	}
 	for _, u := range users {
+		line := "* " + u.Name
 		if u.Name == currentUsername {
-			fmt.Printf("* %s (current)\n", u.Name)
-		} else {
-			fmt.Printf("* %s\n", u.Name)
+			line += " (current)"
+		}
+		if u.IsAdmin {
+			line += " (admin)"
 		}
+		fmt.Println(line)
 	}
 
 	return nil
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+)
+
+func handleMigrate(a *application.App, cmd application.Command) error {
+	err := checkCMDArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Arguments[0] {
+	case "up":
+		applied, err := a.Migrator.Up(context.Background())
+		for _, migration := range applied {
+			fmt.Printf("applied %s\n", migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Printf("no migrations to run, database is at version %d\n", a.Migrator.Latest())
+		}
+		return nil
+	case "down":
+		migration, err := a.Migrator.Down(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %s\n", migration.Name)
+		return nil
+	case "status":
+		statuses, err := a.Migrator.Status(context.Background())
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if status.AppliedAt.Valid {
+				appliedAt = status.AppliedAt.Time.Format(time.ANSIC)
+			}
+			fmt.Printf("%-24s -- %s\n", appliedAt, status.Name)
+		}
+		return nil
+	case "version":
+		version, err := a.Migrator.Version(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("database version: %d (latest: %d)\n", version, a.Migrator.Latest())
+		return nil
+	default:
+		return fmt.Errorf("[usage] migrate up|down|status|version")
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// canManageFeed reports whether user may rename, transfer or remove feed,
+// which its owner and admins can.
+func canManageFeed(user database.User, feed database.Feed) bool {
+	return user.IsAdmin || (feed.UserID.Valid && feed.UserID.Int64 == user.ID)
+}
+
+// managedFeed looks up the feed at url and checks that user may manage it.
+func managedFeed(a *application.App, user database.User, url string) (database.Feed, error) {
+	feed, err := a.DB.GetFeedByURL(context.Background(), url)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return database.Feed{}, fmt.Errorf("feed not found with url %s", url)
+		}
+		return database.Feed{}, err
+	}
+	if !canManageFeed(user, feed) {
+		return database.Feed{}, fmt.Errorf("only the owner of %s or an admin can change it", feed.Name)
+	}
+	return feed, nil
+}
+
+func handleRemoveFeed(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+	force := false
+	if len(cmd.Arguments) > 1 {
+		if cmd.Arguments[1] != "--force" {
+			return fmt.Errorf("[usage] removefeed <url> [--force]")
+		}
+		force = true
+	}
+	if force && !user.IsAdmin {
+		return fmt.Errorf("only an admin can remove a feed with --force")
+	}
+
+	feed, err := managedFeed(a, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	err = a.InTx(context.Background(), func(q database.Querier) error {
+		followers, err := q.GetFeedFollowerIDs(context.Background(), feed.ID)
+		if err != nil {
+			return err
+		}
+		others := 0
+		for _, id := range followers {
+			if id != user.ID {
+				others++
+			}
+		}
+		if others > 0 && !force {
+			return fmt.Errorf("%s is followed by %d other user(s), an admin can remove it with --force", feed.Name, others)
+		}
+		return removeFeed(q, feed, force)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("feed %s removed\n", feed.Name)
+	return nil
+}
+
+// starredPostsError is returned by removeFeed when posts it would delete
+// are starred.
+type starredPostsError struct {
+	Feed    database.Feed
+	Starred int64
+}
+
+func (e *starredPostsError) Error() string {
+	return fmt.Sprintf("%d star(s) are on posts only found in %s, unstar them first", e.Starred, e.Feed.Name)
+}
+
+// removeFeed deletes feed along with its follows. Posts other feeds also
+// published are handed to one of those feeds; the rest are deleted, which
+// fails with a *starredPostsError when any of them is starred unless force
+// is set.
+func removeFeed(q database.Querier, feed database.Feed, force bool) error {
+	err := q.ReassignFeedPosts(context.Background(), feed.ID)
+	if err != nil {
+		return err
+	}
+
+	starred, err := q.CountFeedStars(context.Background(), feed.ID)
+	if err != nil {
+		return err
+	}
+	if starred > 0 {
+		if !force {
+			return &starredPostsError{Feed: feed, Starred: starred}
+		}
+		err = q.DeleteFeedStars(context.Background(), feed.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = q.DeleteFeedPosts(context.Background(), feed.ID)
+	if err != nil {
+		return err
+	}
+	return q.DeleteFeed(context.Background(), feed.ID)
+}
+
+func handleRenameFeed(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 2)
+	if err != nil {
+		return err
+	}
+
+	feed, err := managedFeed(a, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	err = a.DB.RenameFeed(context.Background(), database.RenameFeedParams{
+		Name:      cmd.Arguments[1],
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("feed %s renamed to %s\n", feed.Name, cmd.Arguments[1])
+	return nil
+}
+
+func handleTransferFeed(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 2)
+	if err != nil {
+		return err
+	}
+
+	feed, err := managedFeed(a, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+	owner, err := a.DB.GetUserByName(context.Background(), cmd.Arguments[1])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found with name %s", cmd.Arguments[1])
+		}
+		return err
+	}
+
+	err = a.DB.SetFeedOwner(context.Background(), database.SetFeedOwnerParams{
+		UserID:    sql.NullInt64{Int64: owner.ID, Valid: true},
+		UpdatedAt: time.Now(),
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("feed %s now belongs to %s\n", feed.Name, owner.Name)
+	return nil
+}
+
+// handleDeleteUser deletes a user. Feeds they created that others follow
+// are handed to the follower who followed first; feeds nobody else follows
+// are removed, unless other users starred their posts, in which case they
+// are kept without an owner. Users can delete themselves, admins can
+// delete anyone.
+func handleDeleteUser(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+
+	target := user
+	if cmd.Arguments[0] != user.Name {
+		if !user.IsAdmin {
+			return fmt.Errorf("only an admin can delete other users")
+		}
+		target, err = a.DB.GetUserByName(context.Background(), cmd.Arguments[0])
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("user not found with name %s", cmd.Arguments[0])
+			}
+			return err
+		}
+	}
+
+	handedOver, removed, kept := 0, 0, 0
+	err = a.InTx(context.Background(), func(q database.Querier) error {
+		err := checkNotLastAdmin(q, target)
+		if err != nil {
+			return err
+		}
+
+		feeds, err := q.GetFeedsByOwner(context.Background(), sql.NullInt64{Int64: target.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+		// deleting the user first drops their follows and stars, which
+		// must not keep their feeds alive, and leaves the feeds ownerless
+		err = q.DeleteUser(context.Background(), target.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, feed := range feeds {
+			followers, err := q.GetFeedFollowerIDs(context.Background(), feed.ID)
+			if err != nil {
+				return err
+			}
+			if len(followers) > 0 {
+				err = q.SetFeedOwner(context.Background(), database.SetFeedOwnerParams{
+					UserID:    sql.NullInt64{Int64: followers[0], Valid: true},
+					UpdatedAt: time.Now(),
+					ID:        feed.ID,
+				})
+				if err != nil {
+					return err
+				}
+				handedOver++
+				continue
+			}
+
+			err = removeFeed(q, feed, false)
+			var starred *starredPostsError
+			if errors.As(err, &starred) {
+				kept++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if target.ID == user.ID {
+		err = a.Config.SetUser("")
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("user %s deleted, %d feed(s) handed over, %d removed\n", target.Name, handedOver, removed)
+	if kept > 0 {
+		fmt.Printf("%d feed(s) kept without an owner because other users starred their posts\n", kept)
+	}
+	return nil
+}
+
+func handleAdmin(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 2)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return fmt.Errorf("only an admin can change who is an admin")
+	}
+
+	var isAdmin bool
+	switch cmd.Arguments[1] {
+	case "on":
+		isAdmin = true
+	case "off":
+		isAdmin = false
+	default:
+		return fmt.Errorf("%s command expects on or off as second argument", cmd.Name)
+	}
+
+	target, err := a.DB.GetUserByName(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found with name %s", cmd.Arguments[0])
+		}
+		return err
+	}
+
+	err = a.InTx(context.Background(), func(q database.Querier) error {
+		if !isAdmin {
+			err := checkNotLastAdmin(q, target)
+			if err != nil {
+				return err
+			}
+		}
+		return q.SetUserAdmin(context.Background(), database.SetUserAdminParams{
+			IsAdmin:   isAdmin,
+			UpdatedAt: time.Now(),
+			ID:        target.ID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if isAdmin {
+		fmt.Printf("%s is now an admin\n", target.Name)
+	} else {
+		fmt.Printf("%s is no longer an admin\n", target.Name)
+	}
+	return nil
+}
+
+// checkNotLastAdmin fails when user is the only admin left, so removing
+// their admin rights would leave nobody able to manage other users' feeds.
+// It locks the admins until q's transaction ends and checks whether user is
+// one of them under that lock, so concurrent changes cannot both pass the
+// check.
+func checkNotLastAdmin(q database.Querier, user database.User) error {
+	admins, err := q.LockAdmins(context.Background())
+	if err != nil {
+		return err
+	}
+	if slices.Contains(admins, user.ID) && len(admins) <= 1 {
+		return fmt.Errorf("%s is the last admin, make another user an admin first", user.Name)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+func handleRead(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+	postID, err := strconv.ParseInt(cmd.Arguments[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	marked, err := a.DB.MarkPostRead(context.Background(), database.MarkPostReadParams{
+		UserID: user.ID,
+		ReadAt: time.Now(),
+		PostID: postID,
+	})
+	if err != nil {
+		return err
+	}
+	if marked == 0 {
+		return fmt.Errorf("post not found with id %d", postID)
+	}
+
+	fmt.Printf("post %d marked as read\n", postID)
+	return nil
+}
+
+func handleUnread(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+	postID, err := strconv.ParseInt(cmd.Arguments[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.DB.MarkPostUnread(context.Background(), database.MarkPostUnreadParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("post %d marked as unread\n", postID)
+	return nil
+}
+
+// handleMarkRead marks posts of followed feeds as read in bulk, optionally
+// restricted to one feed and to posts published before a date.
+func handleMarkRead(a *application.App, cmd application.Command, user database.User) error {
+	params := database.MarkPostsReadParams{
+		UserID: user.ID,
+		ReadAt: time.Now(),
+	}
+
+	for i := 0; i < len(cmd.Arguments); i++ {
+		arg := cmd.Arguments[i]
+		if i+1 >= len(cmd.Arguments) {
+			return fmt.Errorf("[usage] markread [--feed <url>] [--before <date>]")
+		}
+		value := cmd.Arguments[i+1]
+		i++
+
+		switch arg {
+		case "--feed":
+			feed, err := a.DB.GetFeedByURL(context.Background(), value)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("feed not found with url %s", value)
+				}
+				return err
+			}
+			params.FeedID = sql.NullInt64{Int64: feed.ID, Valid: true}
+		case "--before":
+			before, err := tryParseDate(value)
+			if err != nil {
+				return err
+			}
+			params.Before = sql.NullTime{Time: before, Valid: true}
+		default:
+			return fmt.Errorf("[usage] markread [--feed <url>] [--before <date>]")
+		}
+	}
+
+	marked, err := a.DB.MarkPostsRead(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d post(s) marked as read\n", marked)
+	return nil
+}
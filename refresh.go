@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// handleRefresh fetches the selected feeds once and exits, so it can be run
+// from cron instead of keeping agg running. It fails when any feed failed.
+func handleRefresh(a *application.App, cmd application.Command, user database.User) error {
+	feeds, err := selectRefreshFeeds(a, cmd, user)
+	if err != nil {
+		return err
+	}
+	if len(feeds) == 0 {
+		fmt.Println("no feeds to refresh")
+		return nil
+	}
+
+	failed := 0
+	for _, feed := range feeds {
+		inserted, skipped, err := refreshClaimedFeed(a, feed)
+		if err == errFeedLeased {
+			fmt.Printf("* %s: skipped, %v\n", feed.Name, err)
+			continue
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("* %s: error: %v\n", feed.Name, err)
+			if kind := classifyAggError(err); kind == aggTransientDB || kind == aggDBFailure {
+				return err
+			}
+			continue
+		}
+		fmt.Printf("* %s: %d new post(s), %d skipped\n", feed.Name, inserted, skipped)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d feed(s) failed to refresh", failed, len(feeds))
+	}
+	return nil
+}
+
+func selectRefreshFeeds(a *application.App, cmd application.Command, user database.User) ([]database.Feed, error) {
+	if len(cmd.Arguments) == 0 || cmd.Arguments[0] == "--following" {
+		return a.DB.GetFollowedFeeds(context.Background(), user.ID)
+	}
+	if cmd.Arguments[0] == "--all" {
+		return a.DB.GetFeeds(context.Background())
+	}
+
+	return findFeeds(a, cmd.Arguments[0])
+}
+
+// findFeeds returns the feed whose url is urlOrName or, failing that, the
+// feeds named urlOrName.
+func findFeeds(a *application.App, urlOrName string) ([]database.Feed, error) {
+	feed, err := a.DB.GetFeedByURL(context.Background(), urlOrName)
+	if err == nil {
+		return []database.Feed{feed}, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	feeds, err := a.DB.GetFeedsByName(context.Background(), urlOrName)
+	if err != nil {
+		return nil, err
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("feed not found with url or name %s", urlOrName)
+	}
+	return feeds, nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/config"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// pruneBatchSize bounds how many posts a single prune statement deletes,
+// keeping locks short on large tables.
+const pruneBatchSize = 500
+
+// autoPruneInterval is how often agg prunes when auto_prune is set.
+const autoPruneInterval = time.Hour
+
+func handleRetention(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	feed, err := a.DB.GetFeedByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("feed not found with url %s", cmd.Arguments[0])
+		}
+		return err
+	}
+
+	policy, err := a.DB.GetFeedRetention(context.Background(), feed.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if len(cmd.Arguments) == 1 {
+		printRetention(feed, policy)
+		return nil
+	}
+	if !canManageFeed(user, feed) {
+		return fmt.Errorf("only the owner of %s or an admin can change its retention policy", feed.Name)
+	}
+
+	params := database.SetFeedRetentionParams{
+		FeedID:        feed.ID,
+		UpdatedAt:     time.Now(),
+		MaxAgeSeconds: policy.MaxAgeSeconds,
+		MaxPosts:      policy.MaxPosts,
+	}
+	args := cmd.Arguments[1:]
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return fmt.Errorf("[usage] retention <url> [--max-age <age>|default] [--max-posts <n>|default]")
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "--max-age":
+			params.MaxAgeSeconds = sql.NullInt32{}
+			if value != "default" {
+				age, err := config.ParseAge(value)
+				if err != nil {
+					return err
+				}
+				params.MaxAgeSeconds, err = ageSeconds(age)
+				if err != nil {
+					return err
+				}
+			}
+		case "--max-posts":
+			params.MaxPosts = sql.NullInt32{}
+			if value != "default" {
+				n, err := strconv.ParseInt(value, 10, 32)
+				if err != nil || n <= 0 {
+					return fmt.Errorf("--max-posts expects a positive number up to %d, got %s", math.MaxInt32, value)
+				}
+				params.MaxPosts = sql.NullInt32{Int32: int32(n), Valid: true}
+			}
+		default:
+			return fmt.Errorf("[usage] retention <url> [--max-age <age>|default] [--max-posts <n>|default]")
+		}
+		i++
+	}
+
+	err = a.DB.SetFeedRetention(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	printRetention(feed, database.FeedRetention{
+		MaxAgeSeconds: params.MaxAgeSeconds,
+		MaxPosts:      params.MaxPosts,
+	})
+	return nil
+}
+
+func printRetention(feed database.Feed, policy database.FeedRetention) {
+	maxAge, maxPosts := "default", "default"
+	if policy.MaxAgeSeconds.Valid {
+		maxAge = (time.Duration(policy.MaxAgeSeconds.Int32) * time.Second).String()
+	}
+	if policy.MaxPosts.Valid {
+		maxPosts = strconv.Itoa(int(policy.MaxPosts.Int32))
+	}
+	fmt.Printf("retention for %s: max age %s, max posts %s\n", feed.Name, maxAge, maxPosts)
+}
+
+// ageSeconds converts a retention age to the number of seconds stored in
+// max_age_seconds, refusing ages that do not fit.
+func ageSeconds(age time.Duration) (sql.NullInt32, error) {
+	if age > config.MaxAge {
+		return sql.NullInt32{}, fmt.Errorf("age %s is longer than the maximum of %s", age, config.MaxAge)
+	}
+	return sql.NullInt32{Int32: int32(age / time.Second), Valid: true}, nil
+}
+
+func handlePrune(a *application.App, cmd application.Command, user database.User) error {
+	if !user.IsAdmin {
+		return fmt.Errorf("only an admin can prune posts")
+	}
+	dryRun := false
+	for _, arg := range cmd.Arguments {
+		if arg != "--dry-run" {
+			return fmt.Errorf("[usage] prune [--dry-run]")
+		}
+		dryRun = true
+	}
+
+	pruned, err := prunePosts(a, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("%d post(s) would be pruned\n", pruned)
+		return nil
+	}
+	fmt.Printf("%d post(s) pruned\n", pruned)
+	return nil
+}
+
+// prunePosts deletes, in batches, the posts that fall outside their feed's
+// retention policy or the global one from the config, returning how many
+// were deleted. Starred posts are always kept. With dryRun set nothing is
+// deleted and the number of posts that would be is returned instead.
+func prunePosts(a *application.App, dryRun bool) (int64, error) {
+	maxAge, maxPosts, err := a.Config.Retention()
+	if err != nil {
+		return 0, err
+	}
+
+	params := database.GetPrunablePostIDsParams{
+		Now:       time.Now(),
+		BatchSize: pruneBatchSize,
+	}
+	if maxAge > 0 {
+		params.DefaultMaxAgeSeconds, err = ageSeconds(maxAge)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if maxPosts > 0 {
+		params.DefaultMaxPosts = sql.NullInt32{Int32: int32(maxPosts), Valid: true}
+	}
+
+	var pruned int64
+	for {
+		ids, err := a.DB.GetPrunablePostIDs(context.Background(), params)
+		if err != nil {
+			return pruned, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		params.AfterID = ids[len(ids)-1]
+
+		if dryRun {
+			pruned += int64(len(ids))
+			continue
+		}
+
+		deleted, err := a.DB.DeletePosts(context.Background(), ids)
+		if err != nil {
+			return pruned, err
+		}
+		pruned += deleted
+	}
+	if dryRun {
+		return pruned, nil
+	}
+	// clusters left without members, by this prune or by deleted feeds,
+	// are dropped
+	return pruned, a.DB.DeleteEmptyClusters(context.Background())
+}
@@ -1,5 +1,14 @@
 package main
 
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+)
+
 type RSSFeed struct {
 	Channel struct {
 		Title       string    `xml:"title"`
@@ -14,4 +23,201 @@ type RSSItem struct {
 	Link        string `xml:"link"`
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
+	GUID        string `xml:"guid"`
+	Author      string `xml:"author"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+}
+
+// AuthorName returns the item's author, preferring dc:creator which holds
+// a plain name where RSS author is usually an email address.
+func (item RSSItem) AuthorName() string {
+	if item.Creator != "" {
+		return item.Creator
+	}
+	return item.Author
+}
+
+type AtomFeed struct {
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Links    []AtomLink  `xml:"link"`
+	Entries  []AtomEntry `xml:"entry"`
+}
+
+type AtomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []AtomLink `xml:"link"`
+	Author    AtomAuthor `xml:"author"`
+	Summary   string     `xml:"summary"`
+	Content   string     `xml:"content"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type JSONFeed struct {
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	Description string         `json:"description"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+type JSONFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	Summary       string `json:"summary"`
+	ContentText   string `json:"content_text"`
+	ContentHTML   string `json:"content_html"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+	Author        struct {
+		Name string `json:"name"`
+	} `json:"author"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+}
+
+// parseFeed decodes an RSS, Atom or JSON Feed document. Atom and JSON feeds
+// are converted to an RSSFeed so the rest of the program only deals with one
+// shape.
+func parseFeed(data []byte) (*RSSFeed, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var jsonFeed JSONFeed
+		err := json.Unmarshal(trimmed, &jsonFeed)
+		if err != nil {
+			return nil, err
+		}
+		rssFeed := jsonFeed.toRSS()
+		return &rssFeed, nil
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var rssFeed RSSFeed
+	switch root {
+	case "rss":
+		err = xml.Unmarshal(data, &rssFeed)
+		if err != nil {
+			return nil, err
+		}
+	case "feed":
+		var atomFeed AtomFeed
+		err = xml.Unmarshal(data, &atomFeed)
+		if err != nil {
+			return nil, err
+		}
+		rssFeed = atomFeed.toRSS()
+	default:
+		return nil, fmt.Errorf("unsupported feed format: <%s>", root)
+	}
+
+	rssFeed.Channel.Title = html.UnescapeString(rssFeed.Channel.Title)
+	rssFeed.Channel.Description = html.UnescapeString(rssFeed.Channel.Description)
+
+	for i := range rssFeed.Channel.Items {
+		rssFeed.Channel.Items[i].Title = html.UnescapeString(rssFeed.Channel.Items[i].Title)
+		rssFeed.Channel.Items[i].Description = html.UnescapeString(rssFeed.Channel.Items[i].Description)
+		rssFeed.Channel.Items[i].Author = strings.TrimSpace(rssFeed.Channel.Items[i].AuthorName())
+	}
+
+	return &rssFeed, nil
+}
+
+func rootElement(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("invalid feed document: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func (f AtomFeed) toRSS() RSSFeed {
+	var rssFeed RSSFeed
+	rssFeed.Channel.Title = f.Title
+	rssFeed.Channel.Description = f.Subtitle
+	rssFeed.Channel.Link = alternateLink(f.Links)
+
+	for _, entry := range f.Entries {
+		item := RSSItem{
+			Title:       entry.Title,
+			Link:        alternateLink(entry.Links),
+			Description: entry.Summary,
+			PubDate:     entry.Published,
+			GUID:        entry.ID,
+			Author:      entry.Author.Name,
+		}
+		if item.Description == "" {
+			item.Description = entry.Content
+		}
+		if item.PubDate == "" {
+			item.PubDate = entry.Updated
+		}
+		rssFeed.Channel.Items = append(rssFeed.Channel.Items, item)
+	}
+
+	return rssFeed
+}
+
+func (f JSONFeed) toRSS() RSSFeed {
+	var rssFeed RSSFeed
+	rssFeed.Channel.Title = f.Title
+	rssFeed.Channel.Link = f.HomePageURL
+	rssFeed.Channel.Description = f.Description
+
+	for _, jsonItem := range f.Items {
+		item := RSSItem{
+			Title:       jsonItem.Title,
+			Link:        jsonItem.URL,
+			Description: jsonItem.Summary,
+			PubDate:     jsonItem.DatePublished,
+			GUID:        jsonItem.ID,
+			Author:      jsonItem.Author.Name,
+		}
+		if len(jsonItem.Authors) > 0 {
+			item.Author = jsonItem.Authors[0].Name
+		}
+		if item.Description == "" {
+			item.Description = jsonItem.ContentText
+		}
+		if item.Description == "" {
+			item.Description = jsonItem.ContentHTML
+		}
+		if item.PubDate == "" {
+			item.PubDate = jsonItem.DateModified
+		}
+		rssFeed.Channel.Items = append(rssFeed.Channel.Items, item)
+	}
+
+	return rssFeed
+}
+
+func alternateLink(links []AtomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
 }
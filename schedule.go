@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// cadenceSampleSize is how many of a feed's latest posts are used to
+// estimate how often it publishes.
+const cadenceSampleSize = 20
+
+// scheduleNextFetch stores when feed is next due, based on how often it has
+// published recently. The due time is computed by the database clock, like
+// feed leases, so that workers compare both against the same clock.
+func scheduleNextFetch(a *application.App, feed database.Feed) error {
+	minInterval, maxInterval, err := a.Config.PollBounds()
+	if err != nil {
+		return err
+	}
+
+	publishTimes, err := a.DB.GetFeedPublishTimes(context.Background(), database.GetFeedPublishTimesParams{
+		FeedID: feed.ID,
+		Limit:  cadenceSampleSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	interval := adaptiveInterval(publishTimes, minInterval, maxInterval)
+	return a.DB.SetFeedSchedule(context.Background(), database.SetFeedScheduleParams{
+		PollIntervalSeconds: int32(interval / time.Second),
+		ID:                  feed.ID,
+	})
+}
+
+// adaptiveInterval polls a feed twice per median gap between its posts,
+// clamped to [minInterval, maxInterval]. Feeds without enough history are
+// polled at minInterval until a cadence can be observed.
+func adaptiveInterval(publishTimes []time.Time, minInterval, maxInterval time.Duration) time.Duration {
+	if len(publishTimes) < 2 {
+		return minInterval
+	}
+
+	sorted := slices.Clone(publishTimes)
+	slices.SortFunc(sorted, func(a, b time.Time) int { return a.Compare(b) })
+
+	gaps := make([]time.Duration, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, sorted[i].Sub(sorted[i-1]))
+	}
+	slices.Sort(gaps)
+	median := gaps[len(gaps)/2]
+
+	return min(max(median/2, minInterval), maxInterval)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const searchUsage = "[usage] search <query> [--feed <url>] [--since <date>] [--until <date>] [--following] [--limit <n>]"
+
+// handleSearch runs a full-text search over post titles, descriptions and
+// article content. The query uses web search syntax: "quoted phrases", OR,
+// and -excluded words.
+func handleSearch(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	params := database.SearchPostsParams{
+		UserID:    user.ID,
+		PostLimit: 10,
+	}
+	var terms []string
+	for i := 0; i < len(cmd.Arguments); i++ {
+		arg := cmd.Arguments[i]
+		if arg == "--following" {
+			params.FollowedOnly = true
+			continue
+		}
+		if !strings.HasPrefix(arg, "--") {
+			terms = append(terms, arg)
+			continue
+		}
+		if i+1 >= len(cmd.Arguments) {
+			return fmt.Errorf(searchUsage)
+		}
+		i++
+		value := cmd.Arguments[i]
+
+		switch arg {
+		case "--feed":
+			feed, err := a.DB.GetFeedByURL(context.Background(), value)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return fmt.Errorf("feed not found with url %s", value)
+				}
+				return err
+			}
+			params.FeedID = sql.NullInt64{Int64: feed.ID, Valid: true}
+		case "--since":
+			since, err := tryParseDate(value)
+			if err != nil {
+				return err
+			}
+			params.Since = sql.NullTime{Time: since, Valid: true}
+		case "--until":
+			until, err := tryParseDate(value)
+			if err != nil {
+				return err
+			}
+			params.Until = sql.NullTime{Time: until, Valid: true}
+		case "--limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit <= 0 {
+				return fmt.Errorf("--limit expects a positive number, got %s", value)
+			}
+			params.PostLimit = int32(limit)
+		default:
+			return fmt.Errorf(searchUsage)
+		}
+	}
+	if len(terms) == 0 {
+		return fmt.Errorf(searchUsage)
+	}
+	params.Query = strings.Join(terms, " ")
+
+	posts, snippets, hidden, err := searchVisiblePosts(a, user, params)
+	if err != nil {
+		return err
+	}
+
+	if len(posts) == 0 {
+		fmt.Println("no posts found")
+	}
+	for _, post := range posts {
+		printPost(post)
+		fmt.Printf("  %s\n", strings.Join(strings.Fields(snippets[post.ID]), " "))
+	}
+	if hidden > 0 {
+		fmt.Printf("%d post(s) hidden by filters\n", hidden)
+	}
+	return nil
+}
+
+// searchVisiblePosts runs the search and drops the results user's filters
+// hide. Results are ranked, so they cannot be paged by a cursor; instead
+// the search is repeated with a doubled limit until enough results pass
+// the filters or no more posts match. It returns the visible posts, up to
+// params.PostLimit, their snippets and how many results were hidden.
+func searchVisiblePosts(a *application.App, user database.User, params database.SearchPostsParams) ([]database.Post, map[int64]string, int, error) {
+	limit := int(params.PostLimit)
+	for {
+		results, err := a.DB.SearchPosts(context.Background(), params)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		posts := make([]database.Post, 0, len(results))
+		snippets := make(map[int64]string, len(results))
+		for _, result := range results {
+			posts = append(posts, result.Post)
+			snippets[result.Post.ID] = result.Snippet
+		}
+		posts, hidden, err := applyFilters(a, user, posts)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		if len(posts) >= limit || len(results) < int(params.PostLimit) {
+			return posts[:min(len(posts), limit)], snippets, hidden, nil
+		}
+		params.PostLimit *= 2
+	}
+}
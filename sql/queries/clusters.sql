@@ -0,0 +1,6 @@
+-- name: CreateCluster :one
+INSERT INTO clusters (created_at) VALUES ($1) RETURNING id;
+
+-- name: DeleteEmptyClusters :exec
+DELETE FROM clusters
+WHERE NOT EXISTS (SELECT 1 FROM posts WHERE posts.cluster_id = clusters.id);
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- name: CreateFeedSelector :one
+INSERT INTO feed_selectors (feed_id, created_at, updated_at, item_selector, title_selector, link_selector, date_selector, summary_selector)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8
+)
+RETURNING *;
+
+-- name: GetFeedSelector :one
+SELECT * FROM feed_selectors WHERE feed_id = $1;
\ No newline at end of file
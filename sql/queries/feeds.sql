@@ -1,12 +1,11 @@
 -- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+INSERT INTO feeds (created_at, updated_at, name, url, user_id)
 VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5,
-    $6
+    $5
 )
 RETURNING *;
 
@@ -20,5 +19,71 @@ SELECT * FROM feeds WHERE url = $1 LIMIT 1;
 UPDATE feeds SET last_fetched_at = $1, updated_at = $2
 WHERE id = $3;
 
--- name: GetNextFeedToFetch :one
-SELECT * FROM feeds ORDER BY last_fetched_at DESC NULLS FIRST LIMIT 1;
\ No newline at end of file
+-- name: SetFeedFetchFullArticle :exec
+UPDATE feeds SET fetch_full_article = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: SetFeedFetchError :exec
+UPDATE feeds SET last_fetch_error = $1
+WHERE id = $2;
+
+-- name: GetFeedsByName :many
+SELECT * FROM feeds WHERE name = $1;
+
+-- name: GetFollowedFeeds :many
+SELECT feeds.* FROM feeds
+JOIN feeds_follows ON feeds_follows.feeds_id = feeds.id
+WHERE feeds_follows.user_id = $1
+ORDER BY feeds.name;
+
+-- name: ClaimFeed :one
+UPDATE feeds
+SET lease_owner = sqlc.arg(lease_owner),
+    lease_expires_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id = sqlc.arg(id)
+  AND (lease_expires_at IS NULL OR lease_expires_at < now() AT TIME ZONE 'UTC')
+RETURNING *;
+
+-- name: ClaimNextFeed :one
+UPDATE feeds
+SET lease_owner = sqlc.arg(lease_owner),
+    lease_expires_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => sqlc.arg(lease_seconds)::int),
+    last_fetched_at = sqlc.arg(claimed_at),
+    updated_at = sqlc.arg(claimed_at)
+WHERE id = (
+    SELECT id FROM feeds
+    WHERE (lease_expires_at IS NULL OR lease_expires_at < now() AT TIME ZONE 'UTC')
+      AND (next_fetch_at IS NULL OR next_fetch_at <= now() AT TIME ZONE 'UTC')
+    ORDER BY next_fetch_at ASC NULLS FIRST, last_fetched_at ASC NULLS FIRST
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ReleaseFeedLease :exec
+UPDATE feeds SET lease_owner = NULL, lease_expires_at = NULL
+WHERE id = $1 AND lease_owner = $2;
+
+-- name: RenewFeedLease :execrows
+UPDATE feeds SET lease_expires_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id = sqlc.arg(id) AND lease_owner = sqlc.arg(lease_owner);
+
+-- name: SetFeedSchedule :exec
+UPDATE feeds
+SET poll_interval_seconds = sqlc.arg(poll_interval_seconds)::int,
+    next_fetch_at = (now() AT TIME ZONE 'UTC') + make_interval(secs => sqlc.arg(poll_interval_seconds)::int)
+WHERE id = sqlc.arg(id);
+
+-- name: GetFeedsByOwner :many
+SELECT * FROM feeds WHERE user_id = $1 ORDER BY name;
+
+-- name: RenameFeed :exec
+UPDATE feeds SET name = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: SetFeedOwner :exec
+UPDATE feeds SET user_id = $1, updated_at = $2
+WHERE id = $3;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds WHERE id = $1;
\ No newline at end of file
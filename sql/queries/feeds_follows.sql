@@ -1,12 +1,11 @@
 -- name: CreateFeedFollow :one
 WITH inserted AS (
-    INSERT INTO feeds_follows (id, created_at, updated_at, user_id, feeds_id)
+    INSERT INTO feeds_follows (created_at, updated_at, user_id, feeds_id)
     VALUES (
         $1,
         $2,
         $3,
-        $4,
-        $5
+        $4
     )
     RETURNING id, created_at, updated_at, user_id, feeds_id
 )
@@ -30,7 +29,16 @@ SELECT
     feeds_follows.user_id, 
     feeds_follows.feeds_id, 
     users.name AS user_name, 
-    feeds.name AS feed_name
+    COALESCE(feeds_follows.title, feeds.name) AS feed_name,
+    (
+        SELECT COUNT(*) FROM post_sources
+        WHERE post_sources.feed_id = feeds.id
+          AND NOT EXISTS (
+              SELECT 1 FROM post_reads
+              WHERE post_reads.post_id = post_sources.post_id
+                AND post_reads.user_id = feeds_follows.user_id
+          )
+    ) AS unread_count
 FROM feeds_follows
 JOIN users ON users.id = feeds_follows.user_id
 JOIN feeds ON feeds.id = feeds_follows.feeds_id
@@ -39,4 +47,13 @@ WHERE feeds_follows.user_id = $1;
 -- name: DeleteFeedFollow :exec
 DELETE FROM feeds_follows WHERE user_id = $1 AND feeds_id = $2;
 
- 
\ No newline at end of file
+ 
+
+-- name: GetFeedFollow :one
+SELECT * FROM feeds_follows WHERE user_id = $1 AND feeds_id = $2;
+
+-- name: GetFeedFollowerIDs :many
+SELECT user_id FROM feeds_follows WHERE feeds_id = $1 ORDER BY created_at, id;
+
+-- name: SetFollowTitle :exec
+UPDATE feeds_follows SET title = $1, updated_at = $2 WHERE id = $3;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- name: CreateFilter :one
+INSERT INTO filters (created_at, updated_at, user_id, field, kind, pattern)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING *;
+
+-- name: GetFiltersForUser :many
+SELECT * FROM filters WHERE user_id = $1 ORDER BY id;
+
+-- name: DeleteFilter :execrows
+DELETE FROM filters WHERE id = $1 AND user_id = $2;
\ No newline at end of file
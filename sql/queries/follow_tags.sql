@@ -0,0 +1,18 @@
+-- name: AddFollowTag :exec
+INSERT INTO follow_tags (follow_id, tag, created_at)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT (follow_id, tag) DO NOTHING;
+
+-- name: RemoveFollowTag :execrows
+DELETE FROM follow_tags WHERE follow_id = $1 AND tag = $2;
+
+-- name: GetFollowTagsForUser :many
+SELECT follow_tags.follow_id, follow_tags.tag
+FROM follow_tags
+JOIN feeds_follows ON feeds_follows.id = follow_tags.follow_id
+WHERE feeds_follows.user_id = $1
+ORDER BY follow_tags.tag;
\ No newline at end of file
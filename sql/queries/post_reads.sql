@@ -0,0 +1,20 @@
+-- name: MarkPostRead :execrows
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT sqlc.arg(user_id)::bigint, posts.id, sqlc.arg(read_at)::timestamp
+FROM posts
+WHERE posts.id = sqlc.arg(post_id)
+ON CONFLICT (user_id, post_id) DO UPDATE SET read_at = EXCLUDED.read_at;
+
+-- name: MarkPostUnread :execrows
+DELETE FROM post_reads WHERE user_id = $1 AND post_id = $2;
+
+-- name: MarkPostsRead :execrows
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT DISTINCT sqlc.arg(user_id)::bigint, posts.id, sqlc.arg(read_at)::timestamp
+FROM posts
+JOIN post_sources ON post_sources.post_id = posts.id
+JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+WHERE feeds_follows.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(feed_id)::bigint IS NULL OR post_sources.feed_id = sqlc.narg(feed_id))
+  AND (sqlc.narg(before)::timestamp IS NULL OR posts.published_at < sqlc.narg(before))
+ON CONFLICT (user_id, post_id) DO NOTHING;
\ No newline at end of file
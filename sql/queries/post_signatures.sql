@@ -0,0 +1,14 @@
+-- name: CreatePostSignatures :exec
+INSERT INTO post_signatures (post_id, published_at, minhash)
+SELECT item.post_id, item.published_at, item.minhash
+FROM unnest(
+    sqlc.arg(post_ids)::bigint[],
+    sqlc.arg(published_ats)::timestamp[],
+    sqlc.arg(minhashes)::bytea[]
+) AS item(post_id, published_at, minhash);
+
+-- name: GetClusterCandidates :many
+SELECT post_signatures.post_id, post_signatures.published_at, posts.cluster_id, post_signatures.minhash
+FROM post_signatures
+JOIN posts ON posts.id = post_signatures.post_id
+WHERE post_signatures.published_at BETWEEN sqlc.arg(window_start) AND sqlc.arg(window_end);
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: AddPostSources :exec
+INSERT INTO post_sources (post_id, feed_id, created_at, url)
+SELECT item.post_id, sqlc.arg(feed_id)::bigint, sqlc.arg(created_at)::timestamp, item.url
+FROM unnest(
+    sqlc.arg(post_ids)::bigint[],
+    sqlc.arg(urls)::text[]
+) AS item(post_id, url)
+ON CONFLICT (post_id, feed_id) DO NOTHING;
+
+-- name: GetPostSources :many
+SELECT
+    post_sources.post_id,
+    post_sources.feed_id,
+    COALESCE(feeds_follows.title, feeds.name) AS feed_name
+FROM post_sources
+JOIN feeds ON feeds.id = post_sources.feed_id
+LEFT JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    AND feeds_follows.user_id = sqlc.arg(user_id)
+WHERE post_sources.post_id = ANY(sqlc.arg(post_ids)::bigint[])
+ORDER BY post_sources.created_at;
\ No newline at end of file
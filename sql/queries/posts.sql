@@ -1,19 +1,160 @@
--- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, published_at, title, description, url, feed_id)
-VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7,
-    $8
+-- name: CreatePosts :many
+INSERT INTO posts (created_at, updated_at, published_at, title, description, url, feed_id, content, author, guid, canonical_url, fingerprint)
+SELECT
+    sqlc.arg(created_at)::timestamp,
+    sqlc.arg(created_at)::timestamp,
+    item.published_at,
+    item.title,
+    item.description,
+    item.url,
+    sqlc.arg(feed_id)::bigint,
+    NULLIF(item.content, ''),
+    item.author,
+    item.guid,
+    item.canonical_url,
+    item.fingerprint
+FROM unnest(
+    sqlc.arg(published_ats)::timestamp[],
+    sqlc.arg(titles)::text[],
+    sqlc.arg(descriptions)::text[],
+    sqlc.arg(urls)::text[],
+    sqlc.arg(contents)::text[],
+    sqlc.arg(authors)::text[],
+    sqlc.arg(guids)::text[],
+    sqlc.arg(canonical_urls)::text[],
+    sqlc.arg(fingerprints)::text[]
+) AS item(published_at, title, description, url, content, author, guid, canonical_url, fingerprint)
+ON CONFLICT (url) DO NOTHING
+RETURNING id, published_at, title, description, url;
+
+-- name: GetPost :one
+SELECT * FROM posts WHERE id = $1;
+
+-- name: FindDuplicatePosts :many
+SELECT id, url, canonical_url, guid, fingerprint FROM posts
+WHERE url = ANY(sqlc.arg(urls)::text[])
+   OR canonical_url = ANY(sqlc.arg(canonical_urls)::text[])
+   OR (guid <> '' AND guid = ANY(sqlc.arg(guids)::text[]))
+   OR (fingerprint <> '' AND fingerprint = ANY(sqlc.arg(fingerprints)::text[]));
+
+-- name: SetPostClusters :exec
+UPDATE posts SET cluster_id = item.cluster_id
+FROM unnest(
+    sqlc.arg(post_ids)::bigint[],
+    sqlc.arg(cluster_ids)::bigint[]
+) AS item(post_id, cluster_id)
+WHERE posts.id = item.post_id;
+
+-- name: GetClusterMembersForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE posts.cluster_id = ANY(sqlc.arg(cluster_ids)::bigint[])
+AND EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = sqlc.arg(user_id)
+      AND (sqlc.narg(feed_id)::bigint IS NULL OR feeds_follows.feeds_id = sqlc.narg(feed_id))
+      AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = sqlc.narg(tag)
+      ))
 )
-RETURNING *;
+AND (NOT sqlc.arg(unread_only)::boolean OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = sqlc.arg(user_id)
+))
+AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until));
+
+-- name: GetFeedPublishTimes :many
+SELECT posts.published_at FROM post_sources
+JOIN posts ON posts.id = post_sources.post_id
+WHERE post_sources.feed_id = $1
+ORDER BY posts.published_at DESC
+LIMIT $2;
 
--- name: GetPosts :many
-SELECT * FROM posts ORDER BY published_at DESC LIMIT $1;
+-- name: GetPostsForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = sqlc.arg(user_id)
+      AND (sqlc.narg(feed_id)::bigint IS NULL OR feeds_follows.feeds_id = sqlc.narg(feed_id))
+      AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = sqlc.narg(tag)
+      ))
+)
+AND (NOT sqlc.arg(unread_only)::boolean OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = sqlc.arg(user_id)
+))
+AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+AND (sqlc.narg(after_id)::bigint IS NULL
+    OR (posts.published_at, posts.id) < (sqlc.narg(after_published_at)::timestamp, sqlc.narg(after_id)))
+ORDER BY posts.published_at DESC, posts.id DESC
+LIMIT sqlc.arg(post_limit);
+
+-- name: GetPostsForUserOldestFirst :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.published_at, posts.title, posts.description, posts.url, posts.feed_id, posts.content, posts.author, posts.guid, posts.canonical_url, posts.fingerprint, posts.cluster_id FROM posts
+WHERE EXISTS (
+    SELECT 1 FROM post_sources
+    JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+    WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = sqlc.arg(user_id)
+      AND (sqlc.narg(feed_id)::bigint IS NULL OR feeds_follows.feeds_id = sqlc.narg(feed_id))
+      AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+          SELECT 1 FROM follow_tags
+          WHERE follow_tags.follow_id = feeds_follows.id AND follow_tags.tag = sqlc.narg(tag)
+      ))
+)
+AND (NOT sqlc.arg(unread_only)::boolean OR NOT EXISTS (
+    SELECT 1 FROM post_reads
+    WHERE post_reads.post_id = posts.id AND post_reads.user_id = sqlc.arg(user_id)
+))
+AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+AND (sqlc.narg(after_id)::bigint IS NULL
+    OR (posts.published_at, posts.id) > (sqlc.narg(after_published_at)::timestamp, sqlc.narg(after_id)))
+ORDER BY posts.published_at, posts.id
+LIMIT sqlc.arg(post_limit);
+
+-- name: SearchPosts :many
+SELECT sqlc.embed(posts),
+    ts_rank(posts.search_vector, search_query)::real AS rank,
+    ts_headline(
+        'english',
+        posts.title || ' ' || posts.description || ' ' || COALESCE(posts.content, ''),
+        search_query,
+        'MaxFragments=2, MinWords=5, MaxWords=20, StartSel=**, StopSel=**'
+    ) AS snippet
+FROM posts, websearch_to_tsquery('english', sqlc.arg(query)) search_query
+WHERE posts.search_vector @@ search_query
+  AND (sqlc.narg(feed_id)::bigint IS NULL OR EXISTS (
+      SELECT 1 FROM post_sources
+      WHERE post_sources.post_id = posts.id AND post_sources.feed_id = sqlc.narg(feed_id)
+  ))
+  AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+  AND (NOT sqlc.arg(followed_only)::boolean OR EXISTS (
+      SELECT 1 FROM post_sources
+      JOIN feeds_follows ON feeds_follows.feeds_id = post_sources.feed_id
+      WHERE post_sources.post_id = posts.id AND feeds_follows.user_id = sqlc.arg(user_id)
+  ))
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT sqlc.arg(post_limit);
+
+-- name: ReassignFeedPosts :exec
+UPDATE posts SET feed_id = (
+    SELECT post_sources.feed_id FROM post_sources
+    WHERE post_sources.post_id = posts.id AND post_sources.feed_id <> $1
+    ORDER BY post_sources.created_at
+    LIMIT 1
+)
+WHERE posts.feed_id = $1
+  AND EXISTS (
+      SELECT 1 FROM post_sources
+      WHERE post_sources.post_id = posts.id AND post_sources.feed_id <> $1
+  );
 
--- name: GetPostByUrl :one
-SELECT * FROM posts WHERE url = $1;
\ No newline at end of file
+-- name: DeleteFeedPosts :exec
+DELETE FROM posts WHERE feed_id = $1;
\ No newline at end of file
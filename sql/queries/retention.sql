@@ -0,0 +1,37 @@
+-- name: SetFeedRetention :exec
+INSERT INTO feed_retention (feed_id, updated_at, max_age_seconds, max_posts)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (feed_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at,
+    max_age_seconds = EXCLUDED.max_age_seconds,
+    max_posts = EXCLUDED.max_posts;
+
+-- name: GetFeedRetention :one
+SELECT * FROM feed_retention WHERE feed_id = $1;
+
+-- name: GetPrunablePostIDs :many
+WITH ranked AS (
+    SELECT posts.id, posts.feed_id, posts.published_at,
+        ROW_NUMBER() OVER (PARTITION BY posts.feed_id ORDER BY posts.published_at DESC) AS position
+    FROM posts
+)
+SELECT ranked.id FROM ranked
+LEFT JOIN feed_retention ON feed_retention.feed_id = ranked.feed_id
+WHERE NOT EXISTS (SELECT 1 FROM stars WHERE stars.post_id = ranked.id)
+  AND (
+      ranked.published_at < sqlc.arg(now)::timestamp - make_interval(secs => COALESCE(feed_retention.max_age_seconds, sqlc.narg(default_max_age_seconds)::int))
+      OR ranked.position > COALESCE(feed_retention.max_posts, sqlc.narg(default_max_posts)::int)
+  )
+  AND ranked.id > sqlc.arg(after_id)
+ORDER BY ranked.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: DeletePosts :execrows
+DELETE FROM posts
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+  AND NOT EXISTS (SELECT 1 FROM stars WHERE stars.post_id = posts.id);
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- name: StarPost :execrows
+INSERT INTO stars (user_id, post_id, created_at, note)
+SELECT sqlc.arg(user_id)::bigint, posts.id, sqlc.arg(created_at)::timestamp, sqlc.arg(note)::text
+FROM posts
+WHERE posts.id = sqlc.arg(post_id)
+ON CONFLICT (user_id, post_id) DO UPDATE SET note = COALESCE(NULLIF(EXCLUDED.note, ''), stars.note);
+
+-- name: UnstarPost :execrows
+DELETE FROM stars WHERE user_id = $1 AND post_id = $2;
+
+-- name: GetStarredPosts :many
+SELECT sqlc.embed(posts), stars.note, stars.created_at AS starred_at
+FROM stars
+JOIN posts ON posts.id = stars.post_id
+WHERE stars.user_id = $1
+ORDER BY stars.created_at DESC;
+
+-- name: CountFeedStars :one
+SELECT COUNT(*) FROM stars
+JOIN posts ON posts.id = stars.post_id
+WHERE posts.feed_id = $1;
+
+-- name: DeleteFeedStars :exec
+DELETE FROM stars
+WHERE post_id IN (SELECT id FROM posts WHERE feed_id = $1);
\ No newline at end of file
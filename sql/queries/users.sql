@@ -1,13 +1,16 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, name)
+INSERT INTO users (created_at, updated_at, name, is_admin)
 VALUES (
     $1,
     $2,
     $3,
-    $4
+    NOT EXISTS (SELECT 1 FROM users)
 )
 RETURNING *;
 
+-- name: LockUserRegistration :exec
+SELECT pg_advisory_xact_lock(hashtext('users'));
+
 -- name: GetUserByName :one
 SELECT * FROM users WHERE name = $1  LIMIT 1;
 
@@ -18,4 +21,13 @@ SELECT * FROM users WHERE id = $1  LIMIT 1;
 DELETE FROM users;
 
 -- name: GetUsers :many
-SELECT * FROM users;
\ No newline at end of file
+SELECT * FROM users;
+
+-- name: DeleteUser :exec
+DELETE FROM users WHERE id = $1;
+
+-- name: SetUserAdmin :exec
+UPDATE users SET is_admin = $1, updated_at = $2 WHERE id = $3;
+
+-- name: LockAdmins :many
+SELECT id FROM users WHERE is_admin ORDER BY id FOR UPDATE;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE feed_selectors (
+    feed_id INT NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    item_selector VARCHAR(255) NOT NULL,
+    title_selector VARCHAR(255) NOT NULL,
+    link_selector VARCHAR(255) NOT NULL,
+    date_selector VARCHAR(255) NOT NULL DEFAULT '',
+    summary_selector VARCHAR(255) NOT NULL DEFAULT '',
+    CONSTRAINT fk_feed
+    FOREIGN KEY(feed_id) REFERENCES feeds(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE feed_selectors;
\ No newline at end of file
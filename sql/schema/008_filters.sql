@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN author VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE filters (
+    id SERIAL NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id INT NOT NULL,
+    field VARCHAR(32) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    pattern TEXT NOT NULL,
+    CONSTRAINT fk_user
+    FOREIGN KEY(user_id) REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE filters;
+ALTER TABLE posts DROP COLUMN author;
\ No newline at end of file
@@ -0,0 +1,37 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN guid VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN canonical_url VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+UPDATE posts SET canonical_url = url;
+
+CREATE INDEX idx_posts_guid ON posts(guid) WHERE guid <> '';
+CREATE INDEX idx_posts_canonical_url ON posts(canonical_url);
+CREATE INDEX idx_posts_fingerprint ON posts(fingerprint) WHERE fingerprint <> '';
+
+CREATE TABLE post_sources (
+    post_id INT NOT NULL,
+    feed_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    url VARCHAR(255) NOT NULL,
+
+    PRIMARY KEY(post_id, feed_id),
+
+    CONSTRAINT fk_post
+    FOREIGN KEY(post_id) REFERENCES posts(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_feed
+    FOREIGN KEY(feed_id) REFERENCES feeds(id)
+    ON DELETE CASCADE
+);
+
+INSERT INTO post_sources (post_id, feed_id, created_at, url)
+SELECT id, feed_id, created_at, url FROM posts;
+
+-- +goose Down
+DROP TABLE post_sources;
+DROP INDEX idx_posts_fingerprint;
+DROP INDEX idx_posts_canonical_url;
+DROP INDEX idx_posts_guid;
+ALTER TABLE posts DROP COLUMN fingerprint;
+ALTER TABLE posts DROP COLUMN canonical_url;
+ALTER TABLE posts DROP COLUMN guid;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE posts ADD COLUMN cluster_id INT;
+ALTER TABLE posts ADD CONSTRAINT fk_cluster
+    FOREIGN KEY(cluster_id) REFERENCES posts(id)
+    ON DELETE SET NULL;
+CREATE INDEX idx_posts_cluster_id ON posts(cluster_id);
+
+CREATE TABLE post_signatures (
+    post_id INT NOT NULL PRIMARY KEY,
+    published_at TIMESTAMP NOT NULL,
+    minhash BYTEA NOT NULL,
+    CONSTRAINT fk_post
+    FOREIGN KEY(post_id) REFERENCES posts(id)
+    ON DELETE CASCADE
+);
+CREATE INDEX idx_post_signatures_published_at ON post_signatures(published_at);
+
+-- +goose Down
+DROP TABLE post_signatures;
+DROP INDEX idx_posts_cluster_id;
+ALTER TABLE posts DROP CONSTRAINT fk_cluster;
+ALTER TABLE posts DROP COLUMN cluster_id;
\ No newline at end of file
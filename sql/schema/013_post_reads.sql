@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE post_reads (
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    read_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY(user_id, post_id),
+
+    CONSTRAINT fk_user
+    FOREIGN KEY(user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+    CONSTRAINT fk_post
+    FOREIGN KEY(post_id) REFERENCES posts(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE post_reads;
\ No newline at end of file
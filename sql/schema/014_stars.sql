@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE stars (
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY(user_id, post_id),
+
+    CONSTRAINT fk_user
+    FOREIGN KEY(user_id) REFERENCES users(id)
+    ON DELETE CASCADE,
+    -- starred posts must never be pruned, so deleting one is an error
+    CONSTRAINT fk_post
+    FOREIGN KEY(post_id) REFERENCES posts(id)
+    ON DELETE RESTRICT
+);
+
+-- +goose Down
+DROP TABLE stars;
\ No newline at end of file
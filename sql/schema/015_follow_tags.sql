@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE follow_tags (
+    follow_id INT NOT NULL,
+    tag VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY(follow_id, tag),
+
+    CONSTRAINT fk_follow
+    FOREIGN KEY(follow_id) REFERENCES feeds_follows(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE follow_tags;
\ No newline at end of file
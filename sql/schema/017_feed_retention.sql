@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE feed_retention (
+    feed_id INT NOT NULL PRIMARY KEY,
+    updated_at TIMESTAMP NOT NULL,
+    max_age_seconds INT,
+    max_posts INT,
+    CONSTRAINT fk_feed
+    FOREIGN KEY(feed_id) REFERENCES feeds(id)
+    ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE feed_retention;
\ No newline at end of file
@@ -0,0 +1,102 @@
+-- +goose Up
+-- ids used to be SERIAL columns filled with truncated uuids by the app;
+-- they become BIGINT identity columns generated by the database.
+ALTER TABLE feeds ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE feeds_follows ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE feeds_follows ALTER COLUMN feeds_id TYPE BIGINT;
+ALTER TABLE posts ALTER COLUMN feed_id TYPE BIGINT;
+ALTER TABLE posts ALTER COLUMN cluster_id TYPE BIGINT;
+ALTER TABLE feed_selectors ALTER COLUMN feed_id TYPE BIGINT;
+ALTER TABLE filters ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE post_sources ALTER COLUMN post_id TYPE BIGINT;
+ALTER TABLE post_sources ALTER COLUMN feed_id TYPE BIGINT;
+ALTER TABLE post_signatures ALTER COLUMN post_id TYPE BIGINT;
+ALTER TABLE post_reads ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE post_reads ALTER COLUMN post_id TYPE BIGINT;
+ALTER TABLE stars ALTER COLUMN user_id TYPE BIGINT;
+ALTER TABLE stars ALTER COLUMN post_id TYPE BIGINT;
+ALTER TABLE follow_tags ALTER COLUMN follow_id TYPE BIGINT;
+ALTER TABLE feed_retention ALTER COLUMN feed_id TYPE BIGINT;
+
+ALTER TABLE users ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN id TYPE BIGINT;
+DROP SEQUENCE users_id_seq;
+ALTER TABLE users ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('users', 'id'), GREATEST(MAX(id), 0) + 1, false) FROM users;
+
+ALTER TABLE feeds ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE feeds ALTER COLUMN id TYPE BIGINT;
+DROP SEQUENCE feeds_id_seq;
+ALTER TABLE feeds ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('feeds', 'id'), GREATEST(MAX(id), 0) + 1, false) FROM feeds;
+
+ALTER TABLE feeds_follows ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE feeds_follows ALTER COLUMN id TYPE BIGINT;
+DROP SEQUENCE feeds_follows_id_seq;
+ALTER TABLE feeds_follows ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('feeds_follows', 'id'), GREATEST(MAX(id), 0) + 1, false) FROM feeds_follows;
+
+ALTER TABLE posts ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE posts ALTER COLUMN id TYPE BIGINT;
+DROP SEQUENCE posts_id_seq;
+ALTER TABLE posts ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('posts', 'id'), GREATEST(MAX(id), 0) + 1, false) FROM posts;
+
+-- +goose Down
+-- ids go back to INT columns, whose sequences cannot go past 2^31-1; refuse
+-- before changing anything rather than fail halfway through.
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF GREATEST(
+        (SELECT MAX(id) FROM users),
+        (SELECT MAX(id) FROM feeds),
+        (SELECT MAX(id) FROM feeds_follows),
+        (SELECT MAX(id) FROM posts)
+    ) >= 2147483647 THEN
+        RAISE EXCEPTION 'cannot migrate ids back to INT: the next id would not fit in INT';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE posts ALTER COLUMN id DROP IDENTITY;
+CREATE SEQUENCE posts_id_seq AS INT OWNED BY posts.id;
+ALTER TABLE posts ALTER COLUMN id SET DEFAULT nextval('posts_id_seq');
+SELECT setval('posts_id_seq', GREATEST(MAX(id), 0) + 1, false) FROM posts;
+
+ALTER TABLE feeds_follows ALTER COLUMN id DROP IDENTITY;
+CREATE SEQUENCE feeds_follows_id_seq AS INT OWNED BY feeds_follows.id;
+ALTER TABLE feeds_follows ALTER COLUMN id SET DEFAULT nextval('feeds_follows_id_seq');
+SELECT setval('feeds_follows_id_seq', GREATEST(MAX(id), 0) + 1, false) FROM feeds_follows;
+
+ALTER TABLE feeds ALTER COLUMN id DROP IDENTITY;
+CREATE SEQUENCE feeds_id_seq AS INT OWNED BY feeds.id;
+ALTER TABLE feeds ALTER COLUMN id SET DEFAULT nextval('feeds_id_seq');
+SELECT setval('feeds_id_seq', GREATEST(MAX(id), 0) + 1, false) FROM feeds;
+
+ALTER TABLE users ALTER COLUMN id DROP IDENTITY;
+CREATE SEQUENCE users_id_seq AS INT OWNED BY users.id;
+ALTER TABLE users ALTER COLUMN id SET DEFAULT nextval('users_id_seq');
+SELECT setval('users_id_seq', GREATEST(MAX(id), 0) + 1, false) FROM users;
+
+ALTER TABLE feed_retention ALTER COLUMN feed_id TYPE INT;
+ALTER TABLE follow_tags ALTER COLUMN follow_id TYPE INT;
+ALTER TABLE stars ALTER COLUMN post_id TYPE INT;
+ALTER TABLE stars ALTER COLUMN user_id TYPE INT;
+ALTER TABLE post_reads ALTER COLUMN post_id TYPE INT;
+ALTER TABLE post_reads ALTER COLUMN user_id TYPE INT;
+ALTER TABLE post_signatures ALTER COLUMN post_id TYPE INT;
+ALTER TABLE post_sources ALTER COLUMN feed_id TYPE INT;
+ALTER TABLE post_sources ALTER COLUMN post_id TYPE INT;
+ALTER TABLE filters ALTER COLUMN user_id TYPE INT;
+ALTER TABLE feed_selectors ALTER COLUMN feed_id TYPE INT;
+ALTER TABLE posts ALTER COLUMN cluster_id TYPE INT;
+ALTER TABLE posts ALTER COLUMN feed_id TYPE INT;
+ALTER TABLE feeds_follows ALTER COLUMN feeds_id TYPE INT;
+ALTER TABLE feeds_follows ALTER COLUMN user_id TYPE INT;
+ALTER TABLE feeds ALTER COLUMN user_id TYPE INT;
+ALTER TABLE posts ALTER COLUMN id TYPE INT;
+ALTER TABLE feeds_follows ALTER COLUMN id TYPE INT;
+ALTER TABLE feeds ALTER COLUMN id TYPE INT;
+ALTER TABLE users ALTER COLUMN id TYPE INT;
\ No newline at end of file
@@ -0,0 +1,39 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE users SET is_admin = TRUE
+WHERE id = (SELECT id FROM users ORDER BY created_at, id LIMIT 1);
+
+-- a feed outlives its creator: it becomes ownerless instead of being
+-- deleted along with the posts other users follow it for
+ALTER TABLE feeds ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE feeds DROP CONSTRAINT fk_user;
+ALTER TABLE feeds ADD CONSTRAINT fk_user
+    FOREIGN KEY(user_id) REFERENCES users(id)
+    ON DELETE SET NULL;
+
+ALTER TABLE feeds_follows DROP CONSTRAINT fk_user;
+ALTER TABLE feeds_follows ADD CONSTRAINT fk_user
+    FOREIGN KEY(user_id) REFERENCES users(id)
+    ON DELETE CASCADE;
+ALTER TABLE feeds_follows DROP CONSTRAINT fk_feeds;
+ALTER TABLE feeds_follows ADD CONSTRAINT fk_feeds
+    FOREIGN KEY(feeds_id) REFERENCES feeds(id)
+    ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE feeds_follows DROP CONSTRAINT fk_feeds;
+ALTER TABLE feeds_follows ADD CONSTRAINT fk_feeds
+    FOREIGN KEY(feeds_id) REFERENCES feeds(id);
+ALTER TABLE feeds_follows DROP CONSTRAINT fk_user;
+ALTER TABLE feeds_follows ADD CONSTRAINT fk_user
+    FOREIGN KEY(user_id) REFERENCES users(id);
+
+UPDATE feeds SET user_id = (SELECT id FROM users ORDER BY is_admin DESC, created_at, id LIMIT 1)
+WHERE user_id IS NULL;
+ALTER TABLE feeds DROP CONSTRAINT fk_user;
+ALTER TABLE feeds ADD CONSTRAINT fk_user
+    FOREIGN KEY(user_id) REFERENCES users(id)
+    ON DELETE CASCADE;
+ALTER TABLE feeds ALTER COLUMN user_id SET NOT NULL;
+
+ALTER TABLE users DROP COLUMN is_admin;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE feeds_follows ADD COLUMN title VARCHAR(255);
+
+-- +goose Down
+ALTER TABLE feeds_follows DROP COLUMN title;
\ No newline at end of file
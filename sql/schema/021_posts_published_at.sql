@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX idx_posts_published_at ON posts(published_at, id);
+
+-- +goose Down
+DROP INDEX idx_posts_published_at;
\ No newline at end of file
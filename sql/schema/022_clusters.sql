@@ -0,0 +1,34 @@
+-- +goose Up
+-- Clusters used to be identified by the id of their first post, so pruning
+-- that post set every other member's cluster_id to NULL and broke the
+-- cluster apart. They now have their own key, which each member, the
+-- first post included, points to.
+CREATE TABLE clusters (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL
+);
+INSERT INTO clusters (id, created_at)
+SELECT posts.id, posts.created_at FROM posts
+WHERE EXISTS (SELECT 1 FROM posts AS members WHERE members.cluster_id = posts.id);
+SELECT setval(pg_get_serial_sequence('clusters', 'id'), GREATEST(MAX(id), 0) + 1, false) FROM clusters;
+
+ALTER TABLE posts DROP CONSTRAINT fk_cluster;
+UPDATE posts SET cluster_id = id WHERE id IN (SELECT id FROM clusters);
+ALTER TABLE posts ADD CONSTRAINT fk_cluster
+    FOREIGN KEY(cluster_id) REFERENCES clusters(id)
+    ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE posts DROP CONSTRAINT fk_cluster;
+UPDATE posts SET cluster_id = roots.id
+FROM (
+    SELECT cluster_id, MIN(id) AS id FROM posts
+    WHERE cluster_id IS NOT NULL
+    GROUP BY cluster_id
+) AS roots
+WHERE posts.cluster_id = roots.cluster_id;
+UPDATE posts SET cluster_id = NULL WHERE cluster_id = id;
+ALTER TABLE posts ADD CONSTRAINT fk_cluster
+    FOREIGN KEY(cluster_id) REFERENCES posts(id)
+    ON DELETE SET NULL;
+DROP TABLE clusters;
\ No newline at end of file
@@ -0,0 +1,137 @@
+-- +goose Up
+-- SQLite equivalent of the PostgreSQL migrations in sql/schema, up to
+-- 018_bigint_ids. Timestamps are stored as text in UTC and compared as
+-- strings; search matches text instead of using a tsvector column.
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name VARCHAR(255) NOT NULL UNIQUE
+);
+
+CREATE TABLE feeds (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    last_fetched_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fetch_full_article BOOLEAN NOT NULL DEFAULT FALSE,
+    last_fetch_error TEXT,
+    lease_owner VARCHAR(255),
+    lease_expires_at TIMESTAMP,
+    poll_interval_seconds INTEGER,
+    next_fetch_at TIMESTAMP
+);
+CREATE INDEX idx_feeds_next_fetch_at ON feeds(next_fetch_at);
+
+CREATE TABLE feeds_follows (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    feeds_id INTEGER NOT NULL REFERENCES feeds(id),
+    UNIQUE(user_id, feeds_id)
+);
+
+CREATE TABLE posts (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    url VARCHAR(255) NOT NULL UNIQUE,
+    feed_id INTEGER NOT NULL REFERENCES feeds(id),
+    content TEXT,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    guid VARCHAR(255) NOT NULL DEFAULT '',
+    canonical_url VARCHAR(255) NOT NULL DEFAULT '',
+    fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    cluster_id INTEGER REFERENCES posts(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_posts_guid ON posts(guid) WHERE guid <> '';
+CREATE INDEX idx_posts_canonical_url ON posts(canonical_url);
+CREATE INDEX idx_posts_fingerprint ON posts(fingerprint) WHERE fingerprint <> '';
+CREATE INDEX idx_posts_cluster_id ON posts(cluster_id);
+
+CREATE TABLE feed_selectors (
+    feed_id INTEGER NOT NULL PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    item_selector VARCHAR(255) NOT NULL,
+    title_selector VARCHAR(255) NOT NULL,
+    link_selector VARCHAR(255) NOT NULL,
+    date_selector VARCHAR(255) NOT NULL DEFAULT '',
+    summary_selector VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE filters (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    field VARCHAR(32) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    pattern TEXT NOT NULL
+);
+
+CREATE TABLE post_sources (
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    feed_id INTEGER NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    PRIMARY KEY(post_id, feed_id)
+);
+
+CREATE TABLE post_signatures (
+    post_id INTEGER NOT NULL PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    published_at TIMESTAMP NOT NULL,
+    minhash BLOB NOT NULL
+);
+CREATE INDEX idx_post_signatures_published_at ON post_signatures(published_at);
+
+CREATE TABLE post_reads (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    read_at TIMESTAMP NOT NULL,
+    PRIMARY KEY(user_id, post_id)
+);
+
+CREATE TABLE stars (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- starred posts must never be pruned, so deleting one is an error
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY(user_id, post_id)
+);
+
+CREATE TABLE follow_tags (
+    follow_id INTEGER NOT NULL REFERENCES feeds_follows(id) ON DELETE CASCADE,
+    tag VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY(follow_id, tag)
+);
+
+CREATE TABLE feed_retention (
+    feed_id INTEGER NOT NULL PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+    updated_at TIMESTAMP NOT NULL,
+    max_age_seconds INTEGER,
+    max_posts INTEGER
+);
+
+-- +goose Down
+DROP TABLE feed_retention;
+DROP TABLE follow_tags;
+DROP TABLE stars;
+DROP TABLE post_reads;
+DROP TABLE post_signatures;
+DROP TABLE post_sources;
+DROP TABLE filters;
+DROP TABLE feed_selectors;
+DROP TABLE posts;
+DROP TABLE feeds_follows;
+DROP TABLE feeds;
+DROP TABLE users;
\ No newline at end of file
@@ -0,0 +1,89 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- SQLite equivalent of sql/schema/019_feed_ownership.sql. Foreign keys
+-- cannot be altered in place, so feeds and feeds_follows are rebuilt with
+-- enforcement off, which only takes effect outside a transaction.
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE users SET is_admin = TRUE
+WHERE id = (SELECT id FROM users ORDER BY created_at, id LIMIT 1);
+
+CREATE TABLE feeds_new (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    last_fetched_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL UNIQUE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    fetch_full_article BOOLEAN NOT NULL DEFAULT FALSE,
+    last_fetch_error TEXT,
+    lease_owner VARCHAR(255),
+    lease_expires_at TIMESTAMP,
+    poll_interval_seconds INTEGER,
+    next_fetch_at TIMESTAMP
+);
+INSERT INTO feeds_new SELECT * FROM feeds;
+DROP TABLE feeds;
+ALTER TABLE feeds_new RENAME TO feeds;
+CREATE INDEX idx_feeds_next_fetch_at ON feeds(next_fetch_at);
+
+CREATE TABLE feeds_follows_new (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feeds_id INTEGER NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    UNIQUE(user_id, feeds_id)
+);
+INSERT INTO feeds_follows_new SELECT * FROM feeds_follows;
+DROP TABLE feeds_follows;
+ALTER TABLE feeds_follows_new RENAME TO feeds_follows;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE feeds_follows_old (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    feeds_id INTEGER NOT NULL REFERENCES feeds(id),
+    UNIQUE(user_id, feeds_id)
+);
+INSERT INTO feeds_follows_old SELECT * FROM feeds_follows;
+DROP TABLE feeds_follows;
+ALTER TABLE feeds_follows_old RENAME TO feeds_follows;
+
+UPDATE feeds SET user_id = (SELECT id FROM users ORDER BY is_admin DESC, created_at, id LIMIT 1)
+WHERE user_id IS NULL;
+CREATE TABLE feeds_old (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    last_fetched_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fetch_full_article BOOLEAN NOT NULL DEFAULT FALSE,
+    last_fetch_error TEXT,
+    lease_owner VARCHAR(255),
+    lease_expires_at TIMESTAMP,
+    poll_interval_seconds INTEGER,
+    next_fetch_at TIMESTAMP
+);
+INSERT INTO feeds_old SELECT * FROM feeds;
+DROP TABLE feeds;
+ALTER TABLE feeds_old RENAME TO feeds;
+CREATE INDEX idx_feeds_next_fetch_at ON feeds(next_fetch_at);
+
+ALTER TABLE users DROP COLUMN is_admin;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- +goose Up
+-- SQLite equivalent of sql/schema/020_follow_titles.sql.
+ALTER TABLE feeds_follows ADD COLUMN title VARCHAR(255);
+
+-- +goose Down
+ALTER TABLE feeds_follows DROP COLUMN title;
\ No newline at end of file
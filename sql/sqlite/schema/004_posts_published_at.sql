@@ -0,0 +1,6 @@
+-- +goose Up
+-- SQLite equivalent of sql/schema/021_posts_published_at.sql.
+CREATE INDEX idx_posts_published_at ON posts(published_at, id);
+
+-- +goose Down
+DROP INDEX idx_posts_published_at;
\ No newline at end of file
@@ -0,0 +1,83 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- SQLite equivalent of sql/schema/022_clusters.sql. The cluster_id foreign
+-- key cannot be altered in place, so posts is rebuilt with enforcement off,
+-- which only takes effect outside a transaction.
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE clusters (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL
+);
+INSERT INTO clusters (id, created_at)
+SELECT posts.id, posts.created_at FROM posts
+WHERE EXISTS (SELECT 1 FROM posts AS members WHERE members.cluster_id = posts.id);
+
+CREATE TABLE posts_new (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    url VARCHAR(255) NOT NULL UNIQUE,
+    feed_id INTEGER NOT NULL REFERENCES feeds(id),
+    content TEXT,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    guid VARCHAR(255) NOT NULL DEFAULT '',
+    canonical_url VARCHAR(255) NOT NULL DEFAULT '',
+    fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    cluster_id INTEGER REFERENCES clusters(id) ON DELETE SET NULL
+);
+INSERT INTO posts_new SELECT * FROM posts;
+UPDATE posts_new SET cluster_id = id WHERE id IN (SELECT id FROM clusters);
+DROP TABLE posts;
+ALTER TABLE posts_new RENAME TO posts;
+CREATE INDEX idx_posts_guid ON posts(guid) WHERE guid <> '';
+CREATE INDEX idx_posts_canonical_url ON posts(canonical_url);
+CREATE INDEX idx_posts_fingerprint ON posts(fingerprint) WHERE fingerprint <> '';
+CREATE INDEX idx_posts_cluster_id ON posts(cluster_id);
+CREATE INDEX idx_posts_published_at ON posts(published_at, id);
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE posts_old (
+    id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    url VARCHAR(255) NOT NULL UNIQUE,
+    feed_id INTEGER NOT NULL REFERENCES feeds(id),
+    content TEXT,
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    guid VARCHAR(255) NOT NULL DEFAULT '',
+    canonical_url VARCHAR(255) NOT NULL DEFAULT '',
+    fingerprint VARCHAR(64) NOT NULL DEFAULT '',
+    cluster_id INTEGER REFERENCES posts(id) ON DELETE SET NULL
+);
+INSERT INTO posts_old SELECT * FROM posts;
+UPDATE posts_old SET cluster_id = (
+    SELECT MIN(members.id) FROM posts AS members
+    WHERE members.cluster_id = posts_old.cluster_id
+)
+WHERE cluster_id IS NOT NULL;
+UPDATE posts_old SET cluster_id = NULL WHERE cluster_id = id;
+DROP TABLE posts;
+ALTER TABLE posts_old RENAME TO posts;
+CREATE INDEX idx_posts_guid ON posts(guid) WHERE guid <> '';
+CREATE INDEX idx_posts_canonical_url ON posts(canonical_url);
+CREATE INDEX idx_posts_fingerprint ON posts(fingerprint) WHERE fingerprint <> '';
+CREATE INDEX idx_posts_cluster_id ON posts(cluster_id);
+CREATE INDEX idx_posts_published_at ON posts(published_at, id);
+DROP TABLE clusters;
+
+COMMIT;
+PRAGMA foreign_keys = ON;
\ No newline at end of file
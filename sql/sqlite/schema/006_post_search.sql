@@ -0,0 +1,35 @@
+-- +goose Up
+-- SQLite stand-in for the search_vector column of
+-- sql/schema/016_post_search.sql: an FTS4 index over the posts' text, kept
+-- in sync by triggers. FTS4 is compiled into the SQLite driver gator uses,
+-- unlike FTS5.
+CREATE VIRTUAL TABLE posts_search USING fts4(content='posts', title, description, content, tokenize=porter);
+INSERT INTO posts_search(posts_search) VALUES ('rebuild');
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_search_before_update BEFORE UPDATE OF title, description, content ON posts BEGIN
+    DELETE FROM posts_search WHERE docid = old.id;
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER posts_search_before_delete BEFORE DELETE ON posts BEGIN
+    DELETE FROM posts_search WHERE docid = old.id;
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER posts_search_after_update AFTER UPDATE OF title, description, content ON posts BEGIN
+    INSERT INTO posts_search(docid, title, description, content) VALUES (new.id, new.title, new.description, new.content);
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER posts_search_after_insert AFTER INSERT ON posts BEGIN
+    INSERT INTO posts_search(docid, title, description, content) VALUES (new.id, new.title, new.description, new.content);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER posts_search_after_insert;
+DROP TRIGGER posts_search_after_update;
+DROP TRIGGER posts_search_before_delete;
+DROP TRIGGER posts_search_before_update;
+DROP TABLE posts_search;
\ No newline at end of file
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+func handleStar(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+	postID, err := strconv.ParseInt(cmd.Arguments[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	starred, err := a.DB.StarPost(context.Background(), database.StarPostParams{
+		UserID:    user.ID,
+		CreatedAt: time.Now(),
+		Note:      strings.Join(cmd.Arguments[1:], " "),
+		PostID:    postID,
+	})
+	if err != nil {
+		return err
+	}
+	if starred == 0 {
+		return fmt.Errorf("post not found with id %d", postID)
+	}
+
+	fmt.Printf("post %d starred\n", postID)
+	return nil
+}
+
+func handleUnstar(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+	postID, err := strconv.ParseInt(cmd.Arguments[0], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	unstarred, err := a.DB.UnstarPost(context.Background(), database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		return err
+	}
+	if unstarred == 0 {
+		return fmt.Errorf("post %d is not starred", postID)
+	}
+
+	fmt.Printf("post %d unstarred\n", postID)
+	return nil
+}
+
+// handleStarred lists user's starred posts. Filters are not applied: the
+// posts were saved by user, so hiding them would only lose them.
+func handleStarred(a *application.App, cmd application.Command, user database.User) error {
+	starred, err := a.DB.GetStarredPosts(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, star := range starred {
+		printPost(star.Post)
+		if star.Note != "" {
+			fmt.Printf("  note: %s\n", star.Note)
+		}
+	}
+	return nil
+}
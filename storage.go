@@ -0,0 +1,68 @@
+package main
+
+import (
+	"database/sql"
+	"embed"
+	"strings"
+
+	"github.com/gaba-bouliva/gator/internal/database"
+	"github.com/gaba-bouliva/gator/internal/database/sqlite"
+	"github.com/gaba-bouliva/gator/internal/migrate"
+)
+
+// sqliteScheme prefixes db_url values naming a SQLite file instead of a
+// PostgreSQL server, e.g. sqlite:///home/me/.gator.db.
+const sqliteScheme = "sqlite://"
+
+//go:embed sql/schema/*.sql
+var postgresMigrations embed.FS
+
+//go:embed sql/sqlite/schema/*.sql
+var sqliteMigrations embed.FS
+
+// storage is an open database along with the queries and migrations of
+// its backend.
+type storage struct {
+	db       *sql.DB
+	queries  database.Querier
+	withTx   func(*sql.Tx) database.Querier
+	migrator *migrate.Migrator
+}
+
+// openDatabase connects to the database named by dbURL and returns the
+// storage matching its scheme.
+func openDatabase(dbURL string) (*storage, error) {
+	if path, ok := strings.CutPrefix(dbURL, sqliteScheme); ok {
+		db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL")
+		if err != nil {
+			return nil, err
+		}
+		migrations, err := migrate.Load(sqliteMigrations, "sql/sqlite/schema")
+		if err != nil {
+			return nil, err
+		}
+		queries := sqlite.New(db)
+		return &storage{
+			db:       db,
+			queries:  queries,
+			withTx:   func(tx *sql.Tx) database.Querier { return queries.WithTx(tx) },
+			migrator: migrate.New(db, migrate.SQLite, migrations),
+		}, nil
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := migrate.Load(postgresMigrations, "sql/schema")
+	if err != nil {
+		return nil, err
+	}
+	queries := database.New(db)
+	return &storage{
+		db:       db,
+		queries:  queries,
+		withTx:   func(tx *sql.Tx) database.Querier { return queries.WithTx(tx) },
+		migrator: migrate.New(db, migrate.Postgres, migrations),
+	}, nil
+}
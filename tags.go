@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+const untaggedLabel = "(untagged)"
+
+func handleTag(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 2)
+	if err != nil {
+		return err
+	}
+
+	follow, err := getFollowByURL(a, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range cmd.Arguments[1:] {
+		err = a.DB.AddFollowTag(context.Background(), database.AddFollowTagParams{
+			FollowID:  follow.ID,
+			Tag:       normalizeTag(tag),
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("tagged %s with %s\n", cmd.Arguments[0], strings.Join(cmd.Arguments[1:], ", "))
+	return nil
+}
+
+func handleUntag(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 2)
+	if err != nil {
+		return err
+	}
+
+	follow, err := getFollowByURL(a, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	removed, err := a.DB.RemoveFollowTag(context.Background(), database.RemoveFollowTagParams{
+		FollowID: follow.ID,
+		Tag:      normalizeTag(cmd.Arguments[1]),
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("%s is not tagged %s", cmd.Arguments[0], cmd.Arguments[1])
+	}
+
+	fmt.Printf("removed tag %s from %s\n", cmd.Arguments[1], cmd.Arguments[0])
+	return nil
+}
+
+func getFollowByURL(a *application.App, user database.User, url string) (database.FeedsFollow, error) {
+	feed, err := a.DB.GetFeedByURL(context.Background(), url)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return database.FeedsFollow{}, fmt.Errorf("feed not found with url %s", url)
+		}
+		return database.FeedsFollow{}, err
+	}
+
+	follow, err := a.DB.GetFeedFollow(context.Background(), database.GetFeedFollowParams{
+		UserID:  user.ID,
+		FeedsID: feed.ID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return database.FeedsFollow{}, fmt.Errorf("you are not following %s", url)
+		}
+		return database.FeedsFollow{}, err
+	}
+	return follow, nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// printFollowingByTag lists follows under each of their tags, so a feed
+// with several tags appears several times and untagged feeds come last.
+func printFollowingByTag(a *application.App, user database.User, follows []database.GetFeedFollowsForUserRow) error {
+	tags, err := a.DB.GetFollowTagsForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	followsByID := make(map[int64]database.GetFeedFollowsForUserRow, len(follows))
+	for _, follow := range follows {
+		followsByID[follow.ID] = follow
+	}
+
+	tagged := make(map[int64]bool)
+	currentTag := ""
+	for _, tag := range tags {
+		follow, ok := followsByID[tag.FollowID]
+		if !ok {
+			continue
+		}
+		if tag.Tag != currentTag {
+			currentTag = tag.Tag
+			fmt.Printf("%s:\n", currentTag)
+		}
+		fmt.Printf("  * %s (%d unread)\n", follow.FeedName, follow.UnreadCount)
+		tagged[follow.ID] = true
+	}
+
+	printedHeader := false
+	for _, follow := range follows {
+		if tagged[follow.ID] {
+			continue
+		}
+		if !printedHeader {
+			fmt.Printf("%s:\n", untaggedLabel)
+			printedHeader = true
+		}
+		fmt.Printf("  * %s (%d unread)\n", follow.FeedName, follow.UnreadCount)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaba-bouliva/gator/internal/application"
+	"github.com/gaba-bouliva/gator/internal/database"
+)
+
+// handleTitle sets the title user sees for a feed they follow in place of
+// the feed's name, or goes back to the feed's name when no title is given.
+func handleTitle(a *application.App, cmd application.Command, user database.User) error {
+	err := checkCMDArgs(cmd, 1)
+	if err != nil {
+		return err
+	}
+
+	follow, err := getFollowByURL(a, user, cmd.Arguments[0])
+	if err != nil {
+		return err
+	}
+
+	title := strings.TrimSpace(strings.Join(cmd.Arguments[1:], " "))
+	err = a.DB.SetFollowTitle(context.Background(), database.SetFollowTitleParams{
+		Title:     sql.NullString{String: title, Valid: title != ""},
+		UpdatedAt: time.Now(),
+		ID:        follow.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if title == "" {
+		fmt.Printf("%s is shown under its feed name again\n", cmd.Arguments[0])
+	} else {
+		fmt.Printf("%s is now shown as %s\n", cmd.Arguments[0], title)
+	}
+	return nil
+}